@@ -17,7 +17,8 @@ import (
 type AckNewEventsCountRequest struct {
 	boxID string
 
-	IdentityID string `json:"identity_id"`
+	IdentityID      string  `json:"identity_id"`
+	LastReadEventID *string `json:"last_read_event_id"`
 }
 
 // BindAndValidate ...
@@ -29,6 +30,7 @@ func (req *AckNewEventsCountRequest) BindAndValidate(eCtx echo.Context) error {
 	return v.ValidateStruct(req,
 		v.Field(&req.boxID, v.Required, is.UUIDv4),
 		v.Field(&req.IdentityID, v.Required, is.UUIDv4),
+		v.Field(&req.LastReadEventID, is.UUIDv4),
 	)
 }
 
@@ -45,5 +47,10 @@ func (app *BoxApplication) AckNewEventsCount(ctx context.Context, genReq request
 		return nil, err
 	}
 
+	// the ack of new events means the identity has read them
+	if err := events.MarkAsRead(ctx, app.DB, app.RedConn, req.IdentityID, req.boxID, req.LastReadEventID); err != nil {
+		return nil, merr.From(err).Desc("marking as read")
+	}
+
 	return nil, nil
 }
@@ -2,8 +2,10 @@ package application
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/go-redis/redis/v7"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
 	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
@@ -19,6 +21,13 @@ type BoxApplication struct {
 	filesRepo files.FileStorageRepo
 	selfOrgID string
 
+	// lifetime of direct transfer urls - direct transfers are disabled if zero
+	directTransferTTL time.Duration
+	// uploaded encrypted files are hashed to share the stored data of identical ones
+	deduplicateFiles bool
+	// maximum sizes of uploaded files
+	uploadLimits config.UploadLimits
+
 	identityRepo external.IdentityRepo
 	cryptoRepo   external.CryptoRepo
 }
@@ -28,6 +37,9 @@ func NewBoxApplication(
 	boxDB *sql.DB, ssoDB *sql.DB, redConn *redis.Client,
 	filesRepo files.FileStorageRepo,
 	selfOrgID string,
+	directTransferTTL time.Duration,
+	deduplicateFiles bool,
+	uploadLimits config.UploadLimits,
 
 	identityRepo external.IdentityRepo,
 	cryptoRepo external.CryptoRepo,
@@ -39,6 +51,10 @@ func NewBoxApplication(
 		filesRepo: filesRepo,
 		selfOrgID: selfOrgID,
 
+		directTransferTTL: directTransferTTL,
+		deduplicateFiles:  deduplicateFiles,
+		uploadLimits:      uploadLimits,
+
 		identityRepo: identityRepo,
 		cryptoRepo:   cryptoRepo,
 	}
@@ -0,0 +1,17 @@
+package application
+
+// A box archive is a zip archive containing everything stored about a box.
+// All data stays end-to-end encrypted.
+// It is made of the following entries:
+const (
+	// the computed box as JSON
+	archiveBoxEntry = "box.json"
+	// all events of the box as JSON views, from the oldest one to the most recent one
+	archiveEventsEntry = "events.json"
+	// the members of the box as JSON
+	archiveMembersEntry = "members.json"
+	// the active accesses of the box as JSON views
+	archiveAccessesEntry = "accesses.json"
+	// the directory containing encrypted files, named after their id
+	archiveFilesDir = "files/"
+)
@@ -16,6 +16,7 @@ import (
 // CountSavedFilesRequest ...
 type CountSavedFilesRequest struct {
 	IdentityID string `query:"identity_id" json:"-"`
+	SavedFileSearch
 }
 
 // BindAndValidate ...
@@ -23,9 +24,12 @@ func (req *CountSavedFilesRequest) BindAndValidate(eCtx echo.Context) error {
 	if err := eCtx.Bind(req); err != nil {
 		return merr.From(err).Ori(merr.OriBody)
 	}
-	return v.ValidateStruct(req,
+	if err := v.ValidateStruct(req,
 		v.Field(&req.IdentityID, v.Required, is.UUIDv4),
-	)
+	); err != nil {
+		return err
+	}
+	return req.SavedFileSearch.validate()
 }
 
 // CountSavedFiles ...
@@ -42,7 +46,7 @@ func (app *BoxApplication) CountSavedFiles(ctx context.Context, genReq request.R
 		return nil, merr.Forbidden().Add("identity_id", merr.DVForbidden)
 	}
 
-	count, err := files.CountSavedFilesByIdentityID(ctx, app.DB, req.IdentityID)
+	count, err := files.CountSavedFiles(ctx, app.DB, req.SavedFileSearch.filters(req.IdentityID))
 	if err != nil {
 		return nil, merr.From(err).Desc("counting user saved files")
 	}
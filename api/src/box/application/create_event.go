@@ -39,12 +39,20 @@ func (req *CreateEventRequest) BindAndValidate(eCtx echo.Context) error {
 		v.Field(&req.Type, v.Required, v.In(
 			etype.Memberjoin,
 			etype.Memberleave,
+			etype.Memberpromote,
+			etype.Memberdemote,
 			etype.Msgdelete,
 			etype.Msgedit,
 			etype.Msgfile,
 			etype.Msgtext,
+			etype.Msgreaction,
+			etype.Msgreply,
 			etype.Stateaccessmode,
 			etype.Statekeyshare,
+			etype.Statetitle,
+			etype.Statedescription,
+			etype.Stateretention,
+			etype.Statearchived,
 		)),
 		v.Field(&req.ReferrerID, is.UUIDv4),
 		v.Field(&req.Content, v.When(etype.RequiresContent(req.Type), v.Required)),
@@ -6,6 +6,7 @@ import (
 	v "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/labstack/echo/v4"
+	"github.com/volatiletech/null/v8"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
@@ -18,10 +19,12 @@ import (
 
 // CreateSavedFileRequest ...
 type CreateSavedFileRequest struct {
-	EncryptedFileID   string `json:"encrypted_file_id"`
-	IdentityID        string `json:"identity_id"`
-	EncryptedMetadata string `json:"encrypted_metadata"`
-	KeyFingerprint    string `json:"key_fingerprint"`
+	EncryptedFileID   string      `json:"encrypted_file_id"`
+	IdentityID        string      `json:"identity_id"`
+	EncryptedMetadata string      `json:"encrypted_metadata"`
+	KeyFingerprint    string      `json:"key_fingerprint"`
+	FolderID          null.String `json:"folder_id"`
+	Tags              []string    `json:"tags"`
 }
 
 // BindAndValidate ...
@@ -34,6 +37,8 @@ func (req *CreateSavedFileRequest) BindAndValidate(eCtx echo.Context) error {
 		v.Field(&req.IdentityID, v.Required, is.UUIDv4),
 		v.Field(&req.EncryptedMetadata, v.Required),
 		v.Field(&req.KeyFingerprint, v.Required),
+		v.Field(&req.FolderID, is.UUIDv4),
+		v.Field(&req.Tags, tagsRules...),
 	)
 }
 
@@ -50,6 +55,10 @@ func (app *BoxApplication) CreateSavedFile(ctx context.Context, genReq request.R
 		return nil, merr.Forbidden().Add("identity_id", merr.DVForbidden)
 	}
 
+	if err := app.mustOwnSavedFileFolder(ctx, access.IdentityID, req.FolderID, "folder_id"); err != nil {
+		return nil, err
+	}
+
 	// check identity has access to the original file
 	hasAccess, err := events.HasAccessToFile(
 		ctx, app.DB, app.RedConn,
@@ -62,6 +71,11 @@ func (app *BoxApplication) CreateSavedFile(ctx context.Context, genReq request.R
 		return nil, merr.Forbidden()
 	}
 
+	// the saved file counts in the vault used space unless the identity already uses its stored data
+	if err := app.mustFitInIdentityQuotaWithFile(ctx, req.IdentityID, req.EncryptedFileID); err != nil {
+		return nil, err
+	}
+
 	// generate a new uuid as a saved file ID
 	id, err := uuid.NewString()
 	if err != nil {
@@ -74,6 +88,8 @@ func (app *BoxApplication) CreateSavedFile(ctx context.Context, genReq request.R
 		EncryptedFileID:   req.EncryptedFileID,
 		EncryptedMetadata: req.EncryptedMetadata,
 		KeyFingerprint:    req.KeyFingerprint,
+		FolderID:          req.FolderID,
+		Tags:              req.Tags,
 	}
 	if err := files.CreateSavedFile(ctx, app.DB, &savedFile); err != nil {
 		return nil, merr.From(err).Desc("creating saved file")
@@ -0,0 +1,260 @@
+package application
+
+import (
+	"context"
+	"io"
+	"time"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/format"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+//
+// this file contains direct transfers of encrypted files:
+// after the usual checks, the api issues short-lived urls allowing clients
+// to put or get encrypted files directly on the storage.
+// For the file system storage, urls are signed by the api which also serves them.
+
+// StartDirectUploadRequest ...
+type StartDirectUploadRequest struct {
+	boxID string
+	Size  int64 `json:"size"`
+}
+
+// BindAndValidate ...
+func (req *StartDirectUploadRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	req.boxID = eCtx.Param("bid")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.Size, v.Required, v.Min(int64(1))),
+	)
+}
+
+// StartDirectUpload returns an url to put the encrypted file directly on the storage
+func (app *BoxApplication) StartDirectUpload(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*StartDirectUploadRequest)
+
+	if err := app.mustAllowDirectTransfer(); err != nil {
+		return nil, err
+	}
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Unauthorized()
+	}
+	if err := app.mustBeAbleToUpload(ctx, req.boxID, acc.IdentityID); err != nil {
+		return nil, err
+	}
+	if err := app.mustAcceptBoxFile(ctx, req.boxID, req.Size); err != nil {
+		return nil, err
+	}
+
+	upload, err := files.StartDirectUpload(ctx, app.RedConn, app.filesRepo, req.boxID, acc.IdentityID, req.Size, app.directTransferTTL)
+	if err != nil {
+		return nil, merr.From(err).Desc("starting direct upload")
+	}
+	return upload, nil
+}
+
+// ConfirmDirectUploadRequest ...
+type ConfirmDirectUploadRequest struct {
+	boxID  string
+	fileID string
+
+	MsgEncContent string `json:"msg_encrypted_content"`
+	MsgPubKey     string `json:"msg_public_key"`
+	MsgTTL        *int64 `json:"msg_ttl"`
+	// MsgReferrerID is set to upload the file as a new version of the file of this msg.file
+	MsgReferrerID *string `json:"msg_referrer_id"`
+}
+
+// BindAndValidate ...
+func (req *ConfirmDirectUploadRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	req.boxID = eCtx.Param("bid")
+	req.fileID = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.fileID, v.Required, is.UUIDv4),
+		v.Field(&req.MsgEncContent, v.Required, v.Match(format.UnpaddedURLSafeBase64)),
+		v.Field(&req.MsgPubKey, v.Required),
+		v.Field(&req.MsgTTL, v.Min(int64(1)), v.Max(events.MaxExpirationSeconds), v.When(req.MsgReferrerID != nil, v.Nil)),
+		v.Field(&req.MsgReferrerID, is.UUIDv4),
+	)
+}
+
+// ConfirmDirectUpload creates the encrypted file and the msg.file event
+// once the client has put the file on the storage
+func (app *BoxApplication) ConfirmDirectUpload(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*ConfirmDirectUploadRequest)
+
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Unauthorized()
+	}
+	upload, err := files.GetDirectUpload(ctx, app.RedConn, req.fileID)
+	if err != nil {
+		return nil, merr.From(err).Desc("getting direct upload")
+	}
+	// a direct upload is only visible to its starter
+	if upload.IdentityID != acc.IdentityID || upload.BoxID != req.boxID {
+		return nil, merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	// the membership, the box state and the used space might have changed since the upload start
+	if err := app.mustBeAbleToUpload(ctx, upload.BoxID, upload.IdentityID); err != nil {
+		return nil, err
+	}
+	if err := app.mustAcceptBoxFile(ctx, upload.BoxID, upload.Size); err != nil {
+		return nil, err
+	}
+
+	e, err := newMsgFileEvent(ctx, upload.BoxID, upload.IdentityID, upload.EncryptedFileID, req.MsgEncContent, req.MsgPubKey, req.MsgTTL, req.MsgReferrerID)
+	if err != nil {
+		return nil, merr.From(err).Desc("creating msg file event")
+	}
+
+	if err := files.ConfirmDirectUpload(ctx, app.RedConn, app.filesRepo, upload); err != nil {
+		return nil, merr.From(err).Desc("confirming direct upload")
+	}
+
+	// create the encrypted file entity
+	eFile := files.EncryptedFile{
+		ID:   upload.EncryptedFileID,
+		Size: upload.Size,
+	}
+	if err := app.createStoredEncryptedFile(ctx, &eFile); err != nil {
+		if delErr := app.filesRepo.Delete(ctx, upload.EncryptedFileID); delErr != nil {
+			return nil, merr.From(err).Descf("deleting file: %v", delErr)
+		}
+		return nil, err
+	}
+
+	return app.createMsgFileEvent(ctx, e, upload.EncryptedFileID, upload.Size)
+}
+
+// GetEncryptedFileURLRequest ...
+type GetEncryptedFileURLRequest struct {
+	fileID string
+}
+
+// BindAndValidate ...
+func (req *GetEncryptedFileURLRequest) BindAndValidate(eCtx echo.Context) error {
+	req.fileID = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.fileID, v.Required, is.UUIDv4),
+	)
+}
+
+// EncryptedFileURLView ...
+type EncryptedFileURLView struct {
+	DownloadURL string    `json:"download_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// GetEncryptedFileURL returns an url to get the encrypted file directly from the storage
+func (app *BoxApplication) GetEncryptedFileURL(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*GetEncryptedFileURLRequest)
+
+	if err := app.mustAllowDirectTransfer(); err != nil {
+		return nil, err
+	}
+	file, err := app.getAccessibleFile(ctx, req.fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := EncryptedFileURLView{ExpiresAt: time.Now().Add(app.directTransferTTL)}
+	if view.DownloadURL, err = app.filesRepo.PresignDownload(ctx, file.StorageKey, app.directTransferTTL); err != nil {
+		return nil, merr.From(err).Desc("presigning download")
+	}
+	return view, nil
+}
+
+// SignedEncryptedFileRequest is a request on an url signed by the file system storage
+type SignedEncryptedFileRequest struct {
+	method string
+	fileID string
+	body   io.Reader
+
+	Expires   int64  `query:"expires"`
+	Size      int64  `query:"size"`
+	Signature string `query:"signature"`
+}
+
+// BindAndValidate ...
+func (req *SignedEncryptedFileRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriQuery)
+	}
+	req.method = eCtx.Request().Method
+	req.fileID = eCtx.Param("id")
+	req.body = eCtx.Request().Body
+	return v.ValidateStruct(req,
+		v.Field(&req.fileID, v.Required, is.UUIDv4),
+		v.Field(&req.Expires, v.Required),
+		v.Field(&req.Signature, v.Required),
+		v.Field(&req.Size, v.When(req.method == echo.PUT, v.Required)),
+	)
+}
+
+// UploadSignedEncryptedFile stores the body on the pending key of the encrypted file
+// it is the target of pre-signed upload urls of the file system storage
+func (app *BoxApplication) UploadSignedEncryptedFile(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*SignedEncryptedFileRequest)
+
+	fs, err := app.verifySignedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	// read one more byte than expected so a bigger file is detected on confirmation
+	if err := fs.Upload(ctx, files.PendingKey(req.fileID), io.LimitReader(req.body, req.Size+1)); err != nil {
+		return nil, merr.From(err).Desc("uploading")
+	}
+	return nil, nil
+}
+
+// DownloadSignedEncryptedFile streams the encrypted file
+// it is the target of pre-signed download urls of the file system storage
+func (app *BoxApplication) DownloadSignedEncryptedFile(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*SignedEncryptedFileRequest)
+
+	fs, err := app.verifySignedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := fs.Download(ctx, req.fileID)
+	if err != nil {
+		return nil, merr.From(err).Desc("downloading")
+	}
+	return reader, nil
+}
+
+func (app *BoxApplication) verifySignedRequest(req *SignedEncryptedFileRequest) (*files.FileSystem, error) {
+	fs, ok := app.filesRepo.(*files.FileSystem)
+	if !ok || app.directTransferTTL == 0 {
+		return nil, merr.NotFound().Desc("signed urls are only served by the file system storage")
+	}
+	if err := fs.VerifySignedURL(req.method, req.fileID, req.Size, req.Expires, req.Signature); err != nil {
+		return nil, merr.From(err).Ori(merr.OriQuery)
+	}
+	return fs, nil
+}
+
+func (app *BoxApplication) mustAllowDirectTransfer() error {
+	if app.directTransferTTL == 0 {
+		return merr.NotFound().Desc("direct transfers are disabled")
+	}
+	return nil
+}
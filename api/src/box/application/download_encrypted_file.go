@@ -2,6 +2,9 @@ package application
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
 
 	v "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -16,12 +19,16 @@ import (
 
 // DownloadEncryptedFileRequest ...
 type DownloadEncryptedFileRequest struct {
-	fileID string
+	fileID      string
+	byteRange   string
+	ifNoneMatch string
 }
 
 // BindAndValidate ...
 func (req *DownloadEncryptedFileRequest) BindAndValidate(eCtx echo.Context) error {
 	req.fileID = eCtx.Param("id")
+	req.byteRange = eCtx.Request().Header.Get("Range")
+	req.ifNoneMatch = eCtx.Request().Header.Get("If-None-Match")
 	return v.ValidateStruct(req,
 		v.Field(&req.fileID, v.Required, is.UUIDv4),
 	)
@@ -31,8 +38,53 @@ func (req *DownloadEncryptedFileRequest) BindAndValidate(eCtx echo.Context) erro
 func (app *BoxApplication) DownloadEncryptedFile(ctx context.Context, genReq request.Request) (interface{}, error) {
 	req := genReq.(*DownloadEncryptedFileRequest)
 
+	file, err := app.getAccessibleFile(ctx, req.fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	// an encrypted file is never modified so its id is a strong validator
+	stream := request.RangedStream{
+		Size: file.Size,
+		ETag: fmt.Sprintf("%q", file.ID),
+	}
+	if req.ifNoneMatch != "" && request.MatchETag(req.ifNoneMatch, stream.ETag) {
+		stream.NotModified = true
+		return stream, nil
+	}
+
+	// download the file - or the requested part of it - then render it
+	stream.Range, err = request.ParseRange(req.byteRange, file.Size)
+	if err != nil {
+		if merr.From(err).Co == merr.RequestedRangeNotSatisfiableCode {
+			stream.Unsatisfiable = true
+			return stream, nil
+		}
+		return nil, err
+	}
+	if stream.Range != nil {
+		stream.ReadCloser, err = files.DownloadRange(ctx, app.filesRepo, *file, stream.Range.Start, stream.Range.Length)
+		if err != nil {
+			return nil, merr.From(err).Desc("downloading range")
+		}
+		return stream, nil
+	}
+	reader, err := files.Download(ctx, app.filesRepo, *file)
+	if err != nil {
+		return nil, merr.From(err).Desc("downloading")
+	}
+	readCloser, ok := reader.(io.ReadCloser)
+	if !ok {
+		readCloser = ioutil.NopCloser(reader)
+	}
+	stream.ReadCloser = readCloser
+	return stream, nil
+}
+
+// getAccessibleFile returns the encrypted file if the current identity has access to it
+func (app *BoxApplication) getAccessibleFile(ctx context.Context, fileID string) (*files.EncryptedFile, error) {
 	// check the file does exist
-	_, err := files.Get(ctx, app.DB, req.fileID)
+	file, err := files.Get(ctx, app.DB, fileID)
 	if err != nil {
 		return nil, merr.From(err).Desc("finding msg.file event")
 	}
@@ -42,18 +94,12 @@ func (app *BoxApplication) DownloadEncryptedFile(ctx context.Context, genReq req
 	if acc == nil {
 		return nil, merr.Unauthorized()
 	}
-	allowed, err := events.HasAccessOrHasSavedFile(ctx, app.DB, app.RedConn, acc.IdentityID, req.fileID)
+	allowed, err := events.HasAccessOrHasSavedFile(ctx, app.DB, app.RedConn, acc.IdentityID, fileID)
 	if err != nil {
 		return nil, merr.From(err).Desc("checking access to file")
 	}
 	if !allowed {
 		return nil, merr.Forbidden()
 	}
-
-	// download the file then render it
-	readCloser, err := files.Download(ctx, app.filesRepo, req.fileID)
-	if err != nil {
-		return nil, merr.From(err).Desc("downloading")
-	}
-	return readCloser, nil
+	return file, nil
 }
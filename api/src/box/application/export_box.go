@@ -0,0 +1,183 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// ExportBoxRequest ...
+type ExportBoxRequest struct {
+	boxID string
+}
+
+// BindAndValidate ...
+func (req *ExportBoxRequest) BindAndValidate(eCtx echo.Context) error {
+	req.boxID = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+	)
+}
+
+// ExportBox streams a box archive containing the box, its events, members, accesses and encrypted files.
+// Only admins of the box can export it.
+func (app *BoxApplication) ExportBox(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*ExportBoxRequest)
+
+	// init an identity mapper for the operation
+	identityMapper := app.NewIM()
+
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Unauthorized()
+	}
+	if err := events.MustBoxExists(ctx, app.DB, req.boxID); err != nil {
+		return nil, merr.From(err).Desc("checking exist")
+	}
+	if err := events.MustBeAdmin(ctx, app.DB, req.boxID, acc.IdentityID); err != nil {
+		return nil, err
+	}
+
+	// retrieve all the JSON entries before starting to stream
+	// so errors can still be returned to the client
+	entries := make(map[string]interface{}, 4)
+	box, err := events.GetSimpleBox(ctx, app.DB, identityMapper, req.boxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("getting box")
+	}
+	entries[archiveBoxEntry] = box
+
+	// the admin needs transparent views to identify senders, members and accesses
+	boxEvents, err := events.ListAllByBoxID(ctx, app.DB, req.boxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing events")
+	}
+	eventViews := make([]events.View, len(boxEvents))
+	for i, e := range boxEvents {
+		eventViews[i], err = e.Format(ctx, identityMapper, true)
+		if err != nil {
+			return nil, merr.From(err).Desc("computing event view")
+		}
+	}
+	entries[archiveEventsEntry] = eventViews
+
+	memberIDs, err := events.ListBoxMemberIDs(ctx, app.DB, app.RedConn, req.boxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing box members")
+	}
+	members, err := identityMapper.List(ctx, memberIDs, true)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing identities")
+	}
+	entries[archiveMembersEntry] = members
+
+	accessEvents, err := events.FindActiveAccesses(ctx, app.DB, req.boxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing accesses")
+	}
+	accessViews := make([]events.View, len(accessEvents))
+	for i, e := range accessEvents {
+		accessViews[i], err = e.Format(ctx, identityMapper, true)
+		if err != nil {
+			return nil, merr.From(err).Desc("computing access view")
+		}
+	}
+	entries[archiveAccessesEntry] = accessViews
+
+	// only files still referenced by a non-deleted msg.file are exported
+	fileEvents, err := events.ListFilesForMembersByBoxID(ctx, app.DB, req.boxID, nil, nil)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing file events")
+	}
+	fileIDs := make([]string, len(fileEvents))
+	fileEventIDs := make([]string, len(fileEvents))
+	for i, e := range fileEvents {
+		var content events.MsgFileContent
+		if err := content.Unmarshal(e.JSONContent); err != nil {
+			return nil, merr.From(err).Desc("unmarshalling file content")
+		}
+		fileIDs[i] = content.EncryptedFileID
+		fileEventIDs[i] = e.ID
+	}
+	// all the versions of the files are exported
+	versionFileIDs, err := events.ListFileVersionIDs(ctx, app.DB, fileEventIDs)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing file versions")
+	}
+	fileIDs = append(fileIDs, versionFileIDs...)
+
+	// check all files are stored before starting to stream
+	// so a missing one is reported instead of producing an incomplete archive
+	encryptedFiles := make([]files.EncryptedFile, len(fileIDs))
+	for i, fileID := range fileIDs {
+		file, err := files.Get(ctx, app.DB, fileID)
+		if err != nil {
+			return nil, merr.From(err).Descf("getting file %s", fileID)
+		}
+		if _, err := app.filesRepo.Size(ctx, file.StorageKey); err != nil {
+			if merr.IsANotFound(err) {
+				return nil, merr.Internal().Descf("stored data of file %s not found", fileID)
+			}
+			return nil, merr.From(err).Descf("checking stored data of file %s", fileID)
+		}
+		encryptedFiles[i] = *file
+	}
+
+	// stream the archive while it is written
+	// a failure while streaming aborts the connection so the client does not get a truncated archive
+	reader, writer := io.Pipe()
+	go func() {
+		err := app.writeBoxArchive(ctx, writer, entries, encryptedFiles)
+		if err != nil {
+			logger.FromCtx(ctx).Error().Err(err).Msgf("writing archive of box %s", req.boxID)
+		}
+		// closing with a nil error is equivalent to a classic close
+		_ = writer.CloseWithError(err)
+	}()
+	return reader, nil
+}
+
+func (app *BoxApplication) writeBoxArchive(ctx context.Context, w io.Writer, entries map[string]interface{}, encryptedFiles []files.EncryptedFile) error {
+	zw := zip.NewWriter(w)
+	for name, entry := range entries {
+		entryWriter, err := zw.Create(name)
+		if err != nil {
+			return merr.From(err).Descf("creating %s entry", name)
+		}
+		if err := json.NewEncoder(entryWriter).Encode(entry); err != nil {
+			return merr.From(err).Descf("encoding %s entry", name)
+		}
+	}
+
+	for _, file := range encryptedFiles {
+		data, err := files.Download(ctx, app.filesRepo, file)
+		if err != nil {
+			return merr.From(err).Descf("downloading file %s", file.ID)
+		}
+		entryWriter, err := zw.Create(archiveFilesDir + file.ID)
+		if err != nil {
+			return merr.From(err).Descf("creating file %s entry", file.ID)
+		}
+		_, err = io.Copy(entryWriter, data)
+		if closer, ok := data.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		if err != nil {
+			return merr.From(err).Descf("copying file %s", file.ID)
+		}
+	}
+	return zw.Close()
+}
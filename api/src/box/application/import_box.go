@@ -0,0 +1,318 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"mime/multipart"
+	"strings"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/atomic"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/authz"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/uuid"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/org"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/etype"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/quota"
+)
+
+// ImportBoxRequest ...
+type ImportBoxRequest struct {
+	archive *multipart.FileHeader
+
+	OwnerOrgID *string `form:"owner_org_id"`
+}
+
+// BindAndValidate ...
+func (req *ImportBoxRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	archive, err := eCtx.FormFile("archive")
+	if err != nil {
+		return merr.BadRequest().Ori(merr.OriBody).
+			Add("archive", merr.DVRequired).Desc(err.Error())
+	}
+	req.archive = archive
+	return v.ValidateStruct(req,
+		v.Field(&req.OwnerOrgID, is.UUIDv4),
+	)
+}
+
+// importedTypes are the event types replayed on import
+// members and accesses are not imported since identities may differ from one environment to another
+var importedTypes = map[string]bool{
+	etype.Msgtext:          true,
+	etype.Msgreply:         true,
+	etype.Msgfile:          true,
+	etype.Msgedit:          true,
+	etype.Msgdelete:        true,
+	etype.Statetitle:       true,
+	etype.Statedescription: true,
+}
+
+// ImportBox creates a new box from a box archive.
+// Only admins of the owner organization can import boxes.
+// The importer becomes the creator of the box and the sender of all imported events.
+func (app *BoxApplication) ImportBox(ctx context.Context, genReq request.Request) (_ interface{}, err error) {
+	req := genReq.(*ImportBoxRequest)
+
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Unauthorized()
+	}
+	if authz.IsAMachine(*acc) {
+		return nil, merr.Forbidden().Desc("machines cannot import boxes")
+	}
+
+	// init an identity mapper for the operation
+	identityMapper := app.NewIM()
+
+	// set defaults
+	if req.OwnerOrgID == nil {
+		req.OwnerOrgID = &app.selfOrgID
+	}
+	if err := org.MustBeAdmin(ctx, app.SSODB, *req.OwnerOrgID, acc.IdentityID); err != nil {
+		return nil, merr.Forbidden().Desc("only organization admins can import boxes")
+	}
+
+	// open the archive and index its entries
+	archiveFile, err := req.archive.Open()
+	if err != nil {
+		return nil, merr.Internal().Descf("opening archive: %v", err)
+	}
+	defer archiveFile.Close()
+	zr, err := zip.NewReader(archiveFile, req.archive.Size)
+	if err != nil {
+		return nil, merr.BadRequest().Desc("reading archive").Add("archive", merr.DVMalformed)
+	}
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	var box events.Box
+	if err := decodeArchiveEntry(entries, archiveBoxEntry, &box); err != nil {
+		return nil, err
+	}
+	var eventViews []events.View
+	if err := decodeArchiveEntry(entries, archiveEventsEntry, &eventViews); err != nil {
+		return nil, err
+	}
+
+	// the archived files must be accepted as if they were uploaded to the new box
+	var importedSize int64
+	for name, entry := range entries {
+		if !strings.HasPrefix(name, archiveFilesDir) {
+			continue
+		}
+		size := int64(entry.UncompressedSize64)
+		if err := app.mustFitInOrgFileSize(ctx, *req.OwnerOrgID, size); err != nil {
+			return nil, merr.From(err).Descf("checking size of %s", name)
+		}
+		importedSize += size
+	}
+	if err := app.mustFitInOwnerQuota(ctx, *req.OwnerOrgID, acc.IdentityID, importedSize); err != nil {
+		return nil, err
+	}
+
+	// start a transaction to import the whole box or nothing
+	tr, err := app.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, merr.From(err).Desc("initing transaction")
+	}
+	defer atomic.SQLRollback(ctx, tr, &err)
+
+	// on failure, uploaded files are removed since the operation cannot be rolled back
+	var uploadedFileIDs []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, fileID := range uploadedFileIDs {
+			if delErr := app.filesRepo.Delete(ctx, fileID); delErr != nil {
+				logger.FromCtx(ctx).Warn().Err(delErr).Msgf("deleting imported file %s", fileID)
+			}
+		}
+	}()
+
+	createEvent, err := events.CreateCreateEvent(
+		ctx,
+		tr, app.RedConn, identityMapper,
+		box.Title, box.PublicKey, *req.OwnerOrgID,
+		nil, nil,
+		acc.IdentityID,
+	)
+	if err != nil {
+		return nil, merr.From(err).Desc("creating create event")
+	}
+
+	// replay events in chronological order, binding exported ids to the new ones
+	newIDs := make(map[string]string, len(eventViews))
+	for _, view := range eventViews {
+		if !importedTypes[view.Type] {
+			continue
+		}
+		var referrerID *string
+		if view.ReferrerID.Valid {
+			newReferrerID, ok := newIDs[view.ReferrerID.String]
+			// the referred event has not been imported
+			if !ok {
+				continue
+			}
+			referrerID = &newReferrerID
+		}
+
+		var content types.JSON
+		if view.Content != nil {
+			content = *view.Content
+		}
+		if view.Type == etype.Msgfile || view.Type == etype.Msgedit {
+			var fileID string
+			var missing bool
+			fileID, content, missing, err = app.importEventFile(ctx, tr, entries, view.Type, content)
+			if fileID != "" {
+				uploadedFileIDs = append(uploadedFileIDs, fileID)
+			}
+			if err != nil {
+				return nil, merr.From(err).Descf("importing file of event %s", view.ID)
+			}
+			// the file is not in the archive: the message had been deleted
+			// and the event cannot refer a file of the original box
+			if missing {
+				continue
+			}
+		}
+
+		var e events.Event
+		e, err = events.ImportEvent(ctx, tr, view, content, createEvent.BoxID, acc.IdentityID, referrerID)
+		if err != nil {
+			return nil, merr.From(err).Descf("importing event %s", view.ID)
+		}
+		newIDs[view.ID] = e.ID
+	}
+
+	// recompute the used space of the box
+	usedSpace, err := events.ComputeBoxUsedSpace(ctx, tr, createEvent.BoxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("computing used space")
+	}
+	if err = quota.SetBoxUsedSpace(ctx, tr, createEvent.BoxID, usedSpace); err != nil {
+		return nil, merr.From(err).Desc("setting used space")
+	}
+
+	if err = tr.Commit(); err != nil {
+		return nil, merr.From(err).Desc("committing transaction")
+	}
+
+	return events.GetBoxView(ctx, app.DB, identityMapper, app.RedConn, createEvent.BoxID)
+}
+
+// importEventFile uploads the encrypted file referred by the msg.file content
+// or added as a new version by the msg.edit content under a new id
+// and returns this id alongside the content updated accordingly.
+// The returned id is empty if the event has no file or if the file is not part of the archive,
+// in which case missing is true and the event must not be imported.
+func (app *BoxApplication) importEventFile(
+	ctx context.Context, tr *sql.Tx, entries map[string]*zip.File, eType string, content types.JSON,
+) (fileID string, newContent types.JSON, missing bool, err error) {
+	if eType == etype.Msgedit {
+		var editContent events.MsgEditContent
+		if err := editContent.Unmarshal(content); err != nil {
+			return "", content, false, merr.BadRequest().Descf("unmarshalling edit content: %v", err)
+		}
+		if editContent.NewEncryptedFileID == "" {
+			return "", content, false, nil
+		}
+		entry, ok := entries[archiveFilesDir+editContent.NewEncryptedFileID]
+		if !ok {
+			return "", content, true, nil
+		}
+		fileID, err = app.importFile(ctx, tr, entry)
+		if err != nil {
+			return fileID, content, false, err
+		}
+		editContent.NewEncryptedFileID = fileID
+		// the size set in the archive is not trusted since it is counted in the used space
+		editContent.NewFileSize = int64(entry.UncompressedSize64)
+		if err := content.Marshal(editContent); err != nil {
+			return fileID, content, false, merr.From(err).Desc("marshalling edit content")
+		}
+		return fileID, content, false, nil
+	}
+
+	var fileContent events.MsgFileContent
+	if err := fileContent.Unmarshal(content); err != nil {
+		return "", content, false, merr.BadRequest().Descf("unmarshalling file content: %v", err)
+	}
+	entry, ok := entries[archiveFilesDir+fileContent.EncryptedFileID]
+	if !ok {
+		return "", content, true, nil
+	}
+	fileID, err = app.importFile(ctx, tr, entry)
+	if err != nil {
+		return fileID, content, false, err
+	}
+	fileContent.EncryptedFileID = fileID
+	fileContent.IsSaved = false
+	if err := content.Marshal(fileContent); err != nil {
+		return fileID, content, false, merr.From(err).Desc("marshalling file content")
+	}
+	return fileID, content, false, nil
+}
+
+// importFile uploads the encrypted file of the archive entry under a new id and returns this id.
+func (app *BoxApplication) importFile(ctx context.Context, tr *sql.Tx, entry *zip.File) (string, error) {
+
+	fileID, err := uuid.NewString()
+	if err != nil {
+		return "", merr.From(err).Desc("generating file id")
+	}
+	eFile := files.EncryptedFile{
+		ID:   fileID,
+		Size: int64(entry.UncompressedSize64),
+	}
+	if err := files.Create(ctx, tr, eFile); err != nil {
+		return "", merr.From(err).Desc("creating file")
+	}
+
+	encData, err := entry.Open()
+	if err != nil {
+		return "", merr.BadRequest().Descf("opening file entry: %v", err).Add("archive", merr.DVMalformed)
+	}
+	defer encData.Close()
+	if err := files.Upload(ctx, app.filesRepo, fileID, encData); err != nil {
+		// the id is returned since the file might have been partially uploaded
+		return fileID, merr.From(err).Desc("uploading file")
+	}
+	return fileID, nil
+}
+
+// decodeArchiveEntry into the received value
+func decodeArchiveEntry(entries map[string]*zip.File, name string, value interface{}) error {
+	entry, ok := entries[name]
+	if !ok {
+		return merr.BadRequest().Descf("missing %s entry", name).Add("archive", merr.DVMalformed)
+	}
+	reader, err := entry.Open()
+	if err != nil {
+		return merr.BadRequest().Descf("opening %s entry: %v", name, err).Add("archive", merr.DVMalformed)
+	}
+	defer reader.Close()
+	if err := json.NewDecoder(reader).Decode(value); err != nil {
+		return merr.BadRequest().Descf("decoding %s entry: %v", name, err).Add("archive", merr.DVMalformed)
+	}
+	return nil
+}
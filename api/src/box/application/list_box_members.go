@@ -6,11 +6,13 @@ import (
 	v "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/labstack/echo/v4"
+	"github.com/volatiletech/null/v8"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/realtime"
 )
 
 // ListBoxMembersRequest ...
@@ -26,6 +28,14 @@ func (req *ListBoxMembersRequest) BindAndValidate(eCtx echo.Context) error {
 	)
 }
 
+// BoxMemberView is a box member with information about their reading and presence
+type BoxMemberView struct {
+	events.SenderView
+	LastReadEventID null.String `json:"last_read_event_id"`
+	LastReadAt      null.Time   `json:"last_read_at"`
+	Online          bool        `json:"online"`
+}
+
 // ListBoxMembers ...
 func (app *BoxApplication) ListBoxMembers(ctx context.Context, genReq request.Request) (interface{}, error) {
 	req := genReq.(*ListBoxMembersRequest)
@@ -50,5 +60,29 @@ func (app *BoxApplication) ListBoxMembers(ctx context.Context, genReq request.Re
 	if err != nil {
 		return nil, merr.From(err).Desc("checking admin")
 	}
-	return identityMapper.List(ctx, memberIDs, isAdmin)
+	members, err := identityMapper.List(ctx, memberIDs, isAdmin)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing identities")
+	}
+
+	// bind read receipts to members
+	receipts, err := events.ListReadReceipts(ctx, app.DB, req.boxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing read receipts")
+	}
+	// bind presence to members
+	online, err := realtime.ListOnline(app.RedConn, memberIDs)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing presences")
+	}
+	views := make([]BoxMemberView, len(members))
+	for idx, member := range members {
+		views[idx].SenderView = member
+		views[idx].Online = online[memberIDs[idx]]
+		if receipt, ok := receipts[memberIDs[idx]]; ok {
+			views[idx].LastReadEventID = null.StringFrom(receipt.LastReadEventID)
+			views[idx].LastReadAt = null.TimeFrom(receipt.LastReadAt)
+		}
+	}
+	return views, nil
 }
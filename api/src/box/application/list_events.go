@@ -17,8 +17,11 @@ import (
 // ListEventsRequest ...
 type ListEventsRequest struct {
 	boxID  string
-	Offset *int `query:"offset" json:"-"`
-	Limit  *int `query:"limit" json:"-"`
+	Offset *int    `query:"offset" json:"-"`
+	Limit  *int    `query:"limit" json:"-"`
+	Before *string `query:"before" json:"-"`
+	After  *string `query:"after" json:"-"`
+	Since  *string `query:"since" json:"-"`
 }
 
 // BindAndValidate ...
@@ -29,8 +32,11 @@ func (req *ListEventsRequest) BindAndValidate(eCtx echo.Context) error {
 	req.boxID = eCtx.Param("id")
 	return v.ValidateStruct(req,
 		v.Field(&req.boxID, v.Required, is.UUIDv4),
-		v.Field(&req.Offset, v.Min(0)),
+		v.Field(&req.Offset, v.Min(0), v.When(req.Since != nil, v.Nil)),
 		v.Field(&req.Limit, v.Min(0)),
+		v.Field(&req.Before, is.UUIDv4, v.When(req.Since != nil, v.Nil)),
+		v.Field(&req.After, is.UUIDv4, v.When(req.Since != nil, v.Nil)),
+		v.Field(&req.Since, is.UUIDv4),
 	)
 }
 
@@ -49,7 +55,14 @@ func (app *BoxApplication) ListEvents(ctx context.Context, genReq request.Reques
 		return nil, err
 	}
 
-	boxEvents, err := events.ListForMembersByBoxID(ctx, app.DB, req.boxID, req.Offset, req.Limit)
+	var boxEvents []events.Event
+	var err error
+	if req.Since != nil {
+		// synchronisation mode: events modifying others are also returned
+		boxEvents, err = events.ListSinceForMembersByBoxID(ctx, app.DB, req.boxID, *req.Since, req.Limit)
+	} else {
+		boxEvents, err = events.ListForMembersByBoxID(ctx, app.DB, req.boxID, req.Offset, req.Limit, req.Before, req.After)
+	}
 	if err != nil {
 		return nil, err
 	}
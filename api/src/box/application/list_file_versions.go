@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+)
+
+// ListFileVersionsRequest ...
+type ListFileVersionsRequest struct {
+	boxID   string
+	eventID string
+}
+
+// BindAndValidate ...
+func (req *ListFileVersionsRequest) BindAndValidate(eCtx echo.Context) error {
+	req.boxID = eCtx.Param("id")
+	req.eventID = eCtx.Param("eid")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.eventID, v.Required, is.UUIDv4),
+	)
+}
+
+// ListFileVersions returns the history of the file of a msg.file event.
+// Each version can be downloaded using its encrypted file id.
+func (app *BoxApplication) ListFileVersions(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*ListFileVersionsRequest)
+
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Unauthorized()
+	}
+	if err := events.MustBeMember(ctx, app.DB, app.RedConn, req.boxID, acc.IdentityID); err != nil {
+		return nil, err
+	}
+
+	versions, err := events.ListFileVersions(ctx, app.DB, req.boxID, req.eventID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing file versions")
+	}
+	return versions, nil
+}
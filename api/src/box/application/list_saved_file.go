@@ -18,6 +18,37 @@ type ListSavedFilesRequest struct {
 	Offset     *int   `query:"offset" json:"-"`
 	Limit      *int   `query:"limit" json:"-"`
 	IdentityID string `query:"identity_id" json:"-"`
+	SavedFileSearch
+}
+
+// SavedFileSearch contains the filters and sorting of saved files listing
+// it is exported so echo binds its fields
+type SavedFileSearch struct {
+	FolderID  string   `query:"folder_id" json:"-"`
+	RootOnly  bool     `query:"root_only" json:"-"`
+	Tags      []string `query:"tags" json:"-"`
+	SortBy    string   `query:"sort_by" json:"-"`
+	SortOrder string   `query:"sort_order" json:"-"`
+}
+
+func (search *SavedFileSearch) validate() error {
+	return v.ValidateStruct(search,
+		v.Field(&search.FolderID, is.UUIDv4),
+		v.Field(&search.Tags, tagsRules...),
+		v.Field(&search.SortBy, v.In(files.SavedFileSortCreatedAt, files.SavedFileSortSize)),
+		v.Field(&search.SortOrder, v.In(files.SortOrderAsc, files.SortOrderDesc)),
+	)
+}
+
+func (search SavedFileSearch) filters(identityID string) files.SavedFileFilters {
+	return files.SavedFileFilters{
+		IdentityID: identityID,
+		FolderID:   search.FolderID,
+		RootOnly:   search.RootOnly,
+		Tags:       search.Tags,
+		SortBy:     search.SortBy,
+		SortOrder:  search.SortOrder,
+	}
 }
 
 // BindAndValidate ...
@@ -25,11 +56,14 @@ func (req *ListSavedFilesRequest) BindAndValidate(eCtx echo.Context) error {
 	if err := eCtx.Bind(req); err != nil {
 		return merr.From(err).Ori(merr.OriBody)
 	}
-	return v.ValidateStruct(req,
+	if err := v.ValidateStruct(req,
 		v.Field(&req.IdentityID, v.Required, is.UUIDv4),
 		v.Field(&req.Offset, v.Min(0)),
 		v.Field(&req.Limit, v.Min(0)),
-	)
+	); err != nil {
+		return err
+	}
+	return req.SavedFileSearch.validate()
 }
 
 // ListSavedFiles ...
@@ -46,11 +80,9 @@ func (app *BoxApplication) ListSavedFiles(ctx context.Context, genReq request.Re
 		return nil, merr.Forbidden().Add("identity_id", merr.DVForbidden)
 	}
 
-	filters := files.SavedFileFilters{
-		IdentityID: req.IdentityID,
-		Offset:     req.Offset,
-		Limit:      req.Limit,
-	}
+	filters := req.SavedFileSearch.filters(req.IdentityID)
+	filters.Offset = req.Offset
+	filters.Limit = req.Limit
 
 	return files.ListSavedFiles(ctx, app.DB, filters)
 }
@@ -47,9 +47,9 @@ func (app *BoxApplication) ListUserStorageQuota(ctx context.Context, genReq requ
 	// create then the base quota
 	if noBaseFound(userStorageQuota) {
 		baseQuotum := quota.Quotum{
-			Origin:     "base",
+			Origin:     quota.BaseOrigin,
 			IdentityID: req.IdentityID,
-			Value:      104857600, // default value for newcomers
+			Value:      quota.BaseValue, // default value for newcomers
 		}
 		if err := quota.Create(ctx, app.DB, &baseQuotum); err != nil {
 			return nil, merr.From(err).Desc("creating base quota")
@@ -62,7 +62,7 @@ func (app *BoxApplication) ListUserStorageQuota(ctx context.Context, genReq requ
 // noBaseFound returns true if no quotum with origin base is found in the received slice
 func noBaseFound(userQuota []quota.Quotum) bool {
 	for _, quotum := range userQuota {
-		if quotum.Origin == "base" {
+		if quotum.Origin == quota.BaseOrigin {
 			return false
 		}
 	}
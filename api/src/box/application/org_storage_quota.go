@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/org"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/quota"
+)
+
+// OrgStorageRequest ...
+type OrgStorageRequest struct {
+	orgID string
+}
+
+// BindAndValidate ...
+func (req *OrgStorageRequest) BindAndValidate(eCtx echo.Context) error {
+	req.orgID = eCtx.Param("oid")
+	return v.ValidateStruct(req,
+		v.Field(&req.orgID, v.Required, is.UUIDv4),
+	)
+}
+
+// ListOrgStorageQuota of the organization - an empty list means the organization only has the base quotum
+func (app *BoxApplication) ListOrgStorageQuota(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*OrgStorageRequest)
+
+	if err := app.mustBeOrgAdmin(ctx, req.orgID); err != nil {
+		return nil, err
+	}
+
+	orgQuota, err := quota.ListOrg(ctx, app.DB, req.orgID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing org quota")
+	}
+	return orgQuota, nil
+}
+
+// GetOrgUsedSpace by the boxes owned by the organization
+func (app *BoxApplication) GetOrgUsedSpace(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*OrgStorageRequest)
+
+	if err := app.mustBeOrgAdmin(ctx, req.orgID); err != nil {
+		return nil, err
+	}
+
+	usedSpace, err := app.orgUsedSpace(ctx, req.orgID)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Value int64 `json:"value"`
+	}{Value: usedSpace}, nil
+}
+
+func (app *BoxApplication) mustBeOrgAdmin(ctx context.Context, orgID string) error {
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return merr.Unauthorized()
+	}
+	if err := org.MustBeAdmin(ctx, app.SSODB, orgID, acc.IdentityID); err != nil {
+		return merr.Forbidden()
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package application
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"github.com/volatiletech/null/v8"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/uuid"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+//
+// this file contains the organization of the vault:
+// saved files can be put in hierarchical folders and carry tags.
+// Folder names are encrypted and tags are blind indexes computed by the client,
+// so the server never knows them in clear.
+
+// tagsRules validates the blind-index tags of a saved file
+var tagsRules = []v.Rule{v.Length(0, 32), v.Each(v.Required, v.Length(1, 255))}
+
+// CreateSavedFileFolderRequest ...
+type CreateSavedFileFolderRequest struct {
+	IdentityID    string      `json:"identity_id"`
+	ParentID      null.String `json:"parent_id"`
+	EncryptedName string      `json:"encrypted_name"`
+}
+
+// BindAndValidate ...
+func (req *CreateSavedFileFolderRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	return v.ValidateStruct(req,
+		v.Field(&req.IdentityID, v.Required, is.UUIDv4),
+		v.Field(&req.ParentID, is.UUIDv4),
+		v.Field(&req.EncryptedName, v.Required),
+	)
+}
+
+// CreateSavedFileFolder ...
+func (app *BoxApplication) CreateSavedFileFolder(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*CreateSavedFileFolderRequest)
+
+	access := oidc.GetAccesses(ctx)
+	if access == nil {
+		return nil, merr.Unauthorized()
+	}
+	if req.IdentityID != access.IdentityID {
+		return nil, merr.Forbidden().Add("identity_id", merr.DVForbidden)
+	}
+	if err := app.mustOwnSavedFileFolder(ctx, access.IdentityID, req.ParentID, "parent_id"); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewString()
+	if err != nil {
+		return nil, merr.From(err).Desc("generating folder id")
+	}
+	folder := files.SavedFileFolder{
+		ID:            id,
+		IdentityID:    req.IdentityID,
+		ParentID:      req.ParentID,
+		EncryptedName: req.EncryptedName,
+	}
+	if err := files.CreateSavedFileFolder(ctx, app.DB, &folder); err != nil {
+		return nil, merr.From(err).Desc("creating folder")
+	}
+	return folder, nil
+}
+
+// ListSavedFileFoldersRequest ...
+type ListSavedFileFoldersRequest struct {
+	IdentityID string `query:"identity_id" json:"-"`
+}
+
+// BindAndValidate ...
+func (req *ListSavedFileFoldersRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriQuery)
+	}
+	return v.ValidateStruct(req,
+		v.Field(&req.IdentityID, v.Required, is.UUIDv4),
+	)
+}
+
+// ListSavedFileFolders returns all the folders of the identity
+func (app *BoxApplication) ListSavedFileFolders(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*ListSavedFileFoldersRequest)
+
+	access := oidc.GetAccesses(ctx)
+	if access == nil {
+		return nil, merr.Unauthorized()
+	}
+	if req.IdentityID != access.IdentityID {
+		return nil, merr.Forbidden().Add("identity_id", merr.DVForbidden)
+	}
+	return files.ListSavedFileFolders(ctx, app.DB, req.IdentityID)
+}
+
+// UpdateSavedFileFolderRequest renames and/or moves a folder.
+// A null parent_id moves the folder to the root of the vault.
+type UpdateSavedFileFolderRequest struct {
+	id string
+
+	ParentID      null.String `json:"parent_id"`
+	EncryptedName string      `json:"encrypted_name"`
+}
+
+// BindAndValidate ...
+func (req *UpdateSavedFileFolderRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	req.id = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.id, v.Required, is.UUIDv4),
+		v.Field(&req.ParentID, is.UUIDv4),
+		v.Field(&req.EncryptedName, v.Required),
+	)
+}
+
+// UpdateSavedFileFolder ...
+func (app *BoxApplication) UpdateSavedFileFolder(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*UpdateSavedFileFolderRequest)
+
+	access := oidc.GetAccesses(ctx)
+	if access == nil {
+		return nil, merr.Unauthorized()
+	}
+	folder, err := files.GetSavedFileFolder(ctx, app.DB, req.id)
+	if err != nil {
+		return nil, merr.From(err).Desc("getting folder")
+	}
+	if folder.IdentityID != access.IdentityID {
+		return nil, merr.Forbidden().Add("id", merr.DVForbidden)
+	}
+	if err := app.mustOwnSavedFileFolder(ctx, access.IdentityID, req.ParentID, "parent_id"); err != nil {
+		return nil, err
+	}
+	if req.ParentID.Valid {
+		if err := files.MustNotBeDescendant(ctx, app.DB, req.ParentID.String, folder.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	folder.ParentID = req.ParentID
+	folder.EncryptedName = req.EncryptedName
+	if err := files.UpdateSavedFileFolder(ctx, app.DB, folder); err != nil {
+		return nil, merr.From(err).Desc("updating folder")
+	}
+	return folder, nil
+}
+
+// DeleteSavedFileFolderRequest ...
+type DeleteSavedFileFolderRequest struct {
+	id string
+}
+
+// BindAndValidate ...
+func (req *DeleteSavedFileFolderRequest) BindAndValidate(eCtx echo.Context) error {
+	req.id = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.id, v.Required, is.UUIDv4),
+	)
+}
+
+// DeleteSavedFileFolder removes an empty folder
+func (app *BoxApplication) DeleteSavedFileFolder(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*DeleteSavedFileFolderRequest)
+
+	access := oidc.GetAccesses(ctx)
+	if access == nil {
+		return nil, merr.Unauthorized()
+	}
+	folder, err := files.GetSavedFileFolder(ctx, app.DB, req.id)
+	if err != nil {
+		return nil, merr.From(err).Desc("getting folder")
+	}
+	if folder.IdentityID != access.IdentityID {
+		return nil, merr.Forbidden().Add("id", merr.DVForbidden)
+	}
+	return nil, files.DeleteSavedFileFolder(ctx, app.DB, folder.ID)
+}
+
+// UpdateSavedFileRequest renames, moves and/or tags a saved file.
+// A null folder_id moves the file to the root of the vault.
+type UpdateSavedFileRequest struct {
+	id string
+
+	FolderID          null.String `json:"folder_id"`
+	EncryptedMetadata string      `json:"encrypted_metadata"`
+	Tags              []string    `json:"tags"`
+}
+
+// BindAndValidate ...
+func (req *UpdateSavedFileRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	req.id = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.id, v.Required, is.UUIDv4),
+		v.Field(&req.FolderID, is.UUIDv4),
+		v.Field(&req.EncryptedMetadata, v.Required),
+		v.Field(&req.Tags, tagsRules...),
+	)
+}
+
+// UpdateSavedFile ...
+func (app *BoxApplication) UpdateSavedFile(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*UpdateSavedFileRequest)
+
+	access := oidc.GetAccesses(ctx)
+	if access == nil {
+		return nil, merr.Unauthorized()
+	}
+	savedFile, err := files.GetSavedFile(ctx, app.DB, req.id)
+	if err != nil {
+		return nil, merr.From(err).Desc("getting saved file")
+	}
+	if savedFile.IdentityID != access.IdentityID {
+		return nil, merr.Forbidden().Add("id", merr.DVForbidden)
+	}
+	if err := app.mustOwnSavedFileFolder(ctx, access.IdentityID, req.FolderID, "folder_id"); err != nil {
+		return nil, err
+	}
+
+	savedFile.FolderID = req.FolderID
+	savedFile.EncryptedMetadata = req.EncryptedMetadata
+	savedFile.Tags = req.Tags
+	if savedFile.Tags == nil {
+		savedFile.Tags = []string{}
+	}
+	if err := files.UpdateSavedFile(ctx, app.DB, savedFile); err != nil {
+		return nil, merr.From(err).Desc("updating saved file")
+	}
+	return savedFile, nil
+}
+
+// mustOwnSavedFileFolder checks the optional folder belongs to the identity,
+// the field is used to describe the error
+func (app *BoxApplication) mustOwnSavedFileFolder(ctx context.Context, identityID string, folderID null.String, field string) error {
+	if !folderID.Valid {
+		return nil
+	}
+	folder, err := files.GetSavedFileFolder(ctx, app.DB, folderID.String)
+	if err != nil {
+		if merr.IsANotFound(err) {
+			return merr.NotFound().Add(field, merr.DVNotFound)
+		}
+		return merr.From(err).Desc("getting folder")
+	}
+	if folder.IdentityID != identityID {
+		return merr.Forbidden().Add(field, merr.DVForbidden)
+	}
+	return nil
+}
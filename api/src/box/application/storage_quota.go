@@ -0,0 +1,183 @@
+package application
+
+import (
+	"context"
+
+	"github.com/volatiletech/null/v8"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/org"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/quota"
+)
+
+// mustAcceptBoxFile checks a file of the given size can be sent to the box:
+// it must not exceed the maximum file size nor the storage quota of the box owner,
+// which is the owner organization for boxes owned by an organization, the box creator otherwise.
+func (app *BoxApplication) mustAcceptBoxFile(ctx context.Context, boxID string, size int64) error {
+	createInfo, err := events.GetCreateInfo(ctx, app.DB, boxID)
+	if err != nil {
+		return merr.From(err).Desc("getting create info")
+	}
+	if err := app.mustFitInOrgFileSize(ctx, createInfo.OwnerOrgID, size); err != nil {
+		return err
+	}
+	return app.mustFitInOwnerQuota(ctx, createInfo.OwnerOrgID, createInfo.CreatorID, size)
+}
+
+// mustFitInOwnerQuota checks adding the given size to a box does not exceed the storage quota of its owner,
+// which is the owner organization for boxes owned by an organization, the box creator otherwise.
+func (app *BoxApplication) mustFitInOwnerQuota(ctx context.Context, ownerOrgID, creatorID string, size int64) error {
+	if ownerOrgID != app.selfOrgID {
+		return app.mustFitInOrgQuota(ctx, ownerOrgID, size)
+	}
+	return app.mustFitInIdentityQuota(ctx, creatorID, size)
+}
+
+// mustFitInIdentityQuota checks adding the given size does not exceed the storage quota of the identity.
+func (app *BoxApplication) mustFitInIdentityQuota(ctx context.Context, identityID string, size int64) error {
+	total, err := quota.Total(ctx, app.DB, identityID)
+	if err != nil {
+		return merr.From(err).Desc("getting storage quota")
+	}
+	usedSpace, err := app.identityUsedSpace(ctx, identityID, nil)
+	if err != nil {
+		return err
+	}
+	return mustFit(usedSpace, size, total)
+}
+
+// mustFitInIdentityQuotaWithFile checks referring the existing encrypted file does not exceed
+// the storage quota of the identity. Nothing is added if the identity already uses its stored data.
+func (app *BoxApplication) mustFitInIdentityQuotaWithFile(ctx context.Context, identityID string, fileID string) error {
+	total, err := quota.Total(ctx, app.DB, identityID)
+	if err != nil {
+		return merr.From(err).Desc("getting storage quota")
+	}
+	usedSpace, err := app.identityUsedSpace(ctx, identityID, nil)
+	if err != nil {
+		return err
+	}
+	usedSpaceWithFile, err := app.identityUsedSpace(ctx, identityID, []string{fileID})
+	if err != nil {
+		return err
+	}
+	return mustFit(usedSpace, usedSpaceWithFile-usedSpace, total)
+}
+
+// identityUsedSpace is made of the boxes the identity has created for itself and of its vault,
+// the stored data shared by several files counting once.
+// The additional files are counted as if they were used by the identity.
+func (app *BoxApplication) identityUsedSpace(ctx context.Context, identityID string, fileIDs []string) (int64, error) {
+	creates, err := events.ListCreateByCreatorID(ctx, app.DB, identityID)
+	if err != nil {
+		return 0, merr.From(err).Desc("listing creator box ids")
+	}
+	var boxIDs []string
+	for _, e := range creates {
+		var content events.CreationContent
+		if err := e.JSONContent.Unmarshal(&content); err != nil {
+			return 0, merr.From(err).Desc("unmarshaling creation content")
+		}
+		// boxes owned by an organization count in the organization quota
+		if content.OwnerOrgID == app.selfOrgID {
+			boxIDs = append(boxIDs, e.BoxID)
+		}
+	}
+	usedSpace, err := events.ComputeStoredUsedSpace(ctx, app.DB, events.StoredUsedSpaceFilters{
+		BoxIDs:     boxIDs,
+		IdentityID: null.StringFrom(identityID),
+		FileIDs:    fileIDs,
+	})
+	if err != nil {
+		return 0, merr.From(err).Desc("computing stored used space")
+	}
+	return usedSpace, nil
+}
+
+// mustFitInOrgQuota checks adding the given size does not exceed the storage quota of the organization.
+func (app *BoxApplication) mustFitInOrgQuota(ctx context.Context, orgID string, size int64) error {
+	total, err := quota.TotalOrg(ctx, app.DB, orgID)
+	if err != nil {
+		return merr.From(err).Desc("getting org storage quota")
+	}
+
+	usedSpace, err := app.orgUsedSpace(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return mustFit(usedSpace, size, total)
+}
+
+// orgUsedSpace is made of the boxes owned by the organization,
+// the stored data shared by several files counting once
+func (app *BoxApplication) orgUsedSpace(ctx context.Context, orgID string) (int64, error) {
+	creates, err := events.ListCreateByOwnerOrgID(ctx, app.DB, orgID)
+	if err != nil {
+		return 0, merr.From(err).Desc("listing org box ids")
+	}
+	boxIDs := make([]string, len(creates))
+	for idx, e := range creates {
+		boxIDs[idx] = e.BoxID
+	}
+	usedSpace, err := events.ComputeStoredUsedSpace(ctx, app.DB, events.StoredUsedSpaceFilters{BoxIDs: boxIDs})
+	if err != nil {
+		return 0, merr.From(err).Desc("computing stored used space")
+	}
+	return usedSpace, nil
+}
+
+// mustFitInOrgFileSize checks the size does not exceed the maximum size of files sent to boxes of the organization.
+// The configured maximum is used if the organization does not override it,
+// overrides being capped by the configured hard maximum.
+func (app *BoxApplication) mustFitInOrgFileSize(ctx context.Context, orgID string, size int64) error {
+	maxSize := app.uploadLimits.EncryptedFile
+	if orgID != app.selfOrgID {
+		owner, err := org.GetOrg(ctx, app.SSODB, orgID)
+		if err != nil {
+			return merr.From(err).Desc("getting owner org")
+		}
+		if owner.MaxFileSize.Valid {
+			maxSize = capOverride(owner.MaxFileSize.Int64, app.uploadLimits.OrgMaxFileSize)
+		}
+	}
+	return mustNotExceedSize(size, maxSize)
+}
+
+// mustFitInMemberFileSize checks the size does not exceed the maximum size of files uploaded to the vault of the identity.
+// The configured maximum is used if no organization of the identity overrides it,
+// overrides being capped by the configured hard maximum.
+func (app *BoxApplication) mustFitInMemberFileSize(ctx context.Context, identityID string, size int64) error {
+	maxSize, err := org.GetMemberMaxFileSize(ctx, app.SSODB, identityID, app.uploadLimits.SavedFile)
+	if err != nil {
+		return merr.From(err).Desc("getting max file size")
+	}
+	if maxSize != app.uploadLimits.SavedFile {
+		maxSize = capOverride(maxSize, app.uploadLimits.OrgMaxFileSize)
+	}
+	return mustNotExceedSize(size, maxSize)
+}
+
+// capOverride to the hard maximum, overrides might have been set before it was lowered
+func capOverride(override, hardMax int64) int64 {
+	if override > hardMax {
+		return hardMax
+	}
+	return override
+}
+
+func mustNotExceedSize(size, maxSize int64) error {
+	if size > maxSize {
+		return merr.BadRequest().Ori(merr.OriBody).Add("size", merr.DVInvalid).
+			Descf("the maximum file size is %d bytes", maxSize)
+	}
+	return nil
+}
+
+func mustFit(usedSpace, size, total int64) error {
+	if usedSpace+size > total {
+		return merr.QuotaExceeded().Descf("%d bytes used out of %d, cannot add %d bytes", usedSpace, total, size)
+	}
+	return nil
+}
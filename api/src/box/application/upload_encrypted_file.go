@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
 
 	v "github.com/go-ozzo/ozzo-validation/v4"
@@ -11,6 +12,7 @@ import (
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/uuid"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
 	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
@@ -25,6 +27,9 @@ type UploadEncryptedFileRequest struct {
 
 	MsgEncContent string `form:"msg_encrypted_content"`
 	MsgPubKey     string `form:"msg_public_key"`
+	MsgTTL        *int64 `form:"msg_ttl"`
+	// MsgReferrerID is set to upload the file as a new version of the file of this msg.file
+	MsgReferrerID *string `form:"msg_referrer_id"`
 }
 
 // BindAndValidate ...
@@ -44,7 +49,9 @@ func (req *UploadEncryptedFileRequest) BindAndValidate(eCtx echo.Context) error
 		v.Field(&req.boxID, v.Required, is.UUIDv4),
 		v.Field(&req.MsgEncContent, v.Required, v.Match(format.UnpaddedURLSafeBase64)),
 		v.Field(&req.MsgPubKey, v.Required),
-		v.Field(&req.size, v.Required, v.Max(126*1024*1024).Error("the maximum file size is 126MB")), // @FIXME put the max file size in a configuration
+		v.Field(&req.MsgTTL, v.Min(int64(1)), v.Max(events.MaxExpirationSeconds), v.When(req.MsgReferrerID != nil, v.Nil)),
+		v.Field(&req.MsgReferrerID, is.UUIDv4),
+		v.Field(&req.size, v.Required),
 	)
 }
 
@@ -57,13 +64,11 @@ func (app *BoxApplication) UploadEncryptedFile(ctx context.Context, genReq reque
 	if acc == nil {
 		return nil, merr.Unauthorized()
 	}
-	if err := events.MustBeMember(ctx, app.DB, app.RedConn, req.boxID, acc.IdentityID); err != nil {
+	if err := app.mustBeAbleToUpload(ctx, req.boxID, acc.IdentityID); err != nil {
 		return nil, err
 	}
-
-	// check box exists
-	if err := events.MustBoxExists(ctx, app.DB, req.boxID); err != nil {
-		return nil, merr.From(err).Desc("checking exist")
+	if err := app.mustAcceptBoxFile(ctx, req.boxID, req.size); err != nil {
+		return nil, err
 	}
 
 	// retrieve the raw []byte from the file
@@ -74,36 +79,89 @@ func (app *BoxApplication) UploadEncryptedFile(ctx context.Context, genReq reque
 	defer encData.Close()
 
 	// create the new msg file that will described the upload action
-	e, fileID, err := events.NewMsgFile(ctx, req.boxID, acc.IdentityID, req.MsgEncContent, req.MsgPubKey)
+	fileID, err := uuid.NewString()
+	if err != nil {
+		return nil, merr.From(err).Desc("file id")
+	}
+	e, err := newMsgFileEvent(ctx, req.boxID, acc.IdentityID, fileID, req.MsgEncContent, req.MsgPubKey, req.MsgTTL, req.MsgReferrerID)
 	if err != nil {
 		return nil, merr.From(err).Desc("creating msg file event")
 	}
 
-	// create the encrypted file entity
+	// create the encrypted file entity and upload the encrypted data
 	eFile := files.EncryptedFile{
 		ID:   fileID,
 		Size: req.size,
 	}
-	if err := files.Create(ctx, app.DB, eFile); err != nil {
-		return nil, merr.From(err).Desc("creating file")
+	if err := app.storeEncryptedFile(ctx, &eFile, encData); err != nil {
+		return nil, err
+	}
+
+	return app.createMsgFileEvent(ctx, e, fileID, req.size)
+}
+
+// storeEncryptedFile creates the encrypted file entity then uploads its data.
+// If deduplication is enabled, the stored data of a file with the same content is referenced instead.
+func (app *BoxApplication) storeEncryptedFile(ctx context.Context, eFile *files.EncryptedFile, encData io.ReadSeeker) error {
+	if app.deduplicateFiles {
+		if err := files.CreateDeduplicated(ctx, app.DB, app.filesRepo, eFile, encData); err != nil {
+			return merr.From(err).Desc("creating deduplicated file")
+		}
+		return nil
+	}
+
+	if err := files.Create(ctx, app.DB, *eFile); err != nil {
+		return merr.From(err).Desc("creating file")
+	}
+	if err := files.Upload(ctx, app.filesRepo, eFile.ID, encData); err != nil {
+		return merr.From(err).Desc("uploading file")
+	}
+	return nil
+}
+
+// createStoredEncryptedFile creates the entity of an encrypted file whose data is already stored under its id.
+// If deduplication is enabled, the stored data of a file with the same content is referenced instead.
+func (app *BoxApplication) createStoredEncryptedFile(ctx context.Context, eFile *files.EncryptedFile) error {
+	if app.deduplicateFiles {
+		if err := files.DeduplicateStored(ctx, app.DB, app.filesRepo, eFile); err != nil {
+			return merr.From(err).Desc("deduplicating stored file")
+		}
+		return nil
 	}
 
-	// upload the encrypted data
-	if err := files.Upload(ctx, app.filesRepo, fileID, encData); err != nil {
-		return nil, merr.From(err).Desc("uploading file")
+	if err := files.Create(ctx, app.DB, *eFile); err != nil {
+		return merr.From(err).Desc("creating file")
 	}
+	return nil
+}
 
-	// persist the event in storage - on failure, we try to remove the uploaded file
+// newMsgFileEvent builds the msg.file event describing an uploaded encrypted file
+// or, if a referrer is given, the msg.edit event adding it as a new version of the referred msg.file
+func newMsgFileEvent(
+	ctx context.Context,
+	boxID, senderID, fileID string,
+	encContent, pubKey string,
+	ttl *int64, referrerID *string,
+) (events.Event, error) {
+	if referrerID != nil {
+		return events.NewMsgFileVersion(ctx, boxID, senderID, *referrerID, fileID, encContent, pubKey)
+	}
+	return events.NewMsgFileForID(ctx, boxID, senderID, fileID, encContent, pubKey, ttl)
+}
 
+// createMsgFileEvent persists the msg.file event describing an uploaded encrypted file
+// on failure, we try to remove the uploaded file
+func (app *BoxApplication) createMsgFileEvent(ctx context.Context, e events.Event, fileID string, size int64) (interface{}, error) {
 	// set fileSize in content to compute boxUsedSpace after event is persisted
 	metadata := events.MetadataForUsedSpaceHandler{
-		NewEventSize: req.size,
+		NewEventSize: size,
 	}
 
 	eReq := CreateEventRequest{
 		boxID:               e.BoxID,
 		Type:                e.Type,
 		Content:             e.JSONContent,
+		ReferrerID:          e.ReferrerID.Ptr(),
 		MetadataForHandlers: metadata,
 	}
 	view, err := app.CreateEvent(ctx, &eReq)
@@ -7,6 +7,7 @@ import (
 	v "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/labstack/echo/v4"
+	"github.com/volatiletech/null/v8"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
@@ -22,8 +23,10 @@ type UploadSavedFileRequest struct {
 
 	encFile *multipart.FileHeader
 
-	EncryptedMetadata string `form:"encrypted_metadata"`
-	KeyFingerprint    string `form:"key_fingerprint"`
+	EncryptedMetadata string   `form:"encrypted_metadata"`
+	KeyFingerprint    string   `form:"key_fingerprint"`
+	FolderID          string   `form:"folder_id"`
+	Tags              []string `form:"tags"`
 }
 
 // BindAndValidate ...
@@ -43,7 +46,9 @@ func (req *UploadSavedFileRequest) BindAndValidate(eCtx echo.Context) error {
 		v.Field(&req.identityID, v.Required, is.UUIDv4),
 		v.Field(&req.EncryptedMetadata, v.Required),
 		v.Field(&req.KeyFingerprint, v.Required),
-		v.Field(&req.size, v.Required, v.Max(126*1024*1024).Error("the maximum file size is 126MB")), // @FIXME put the max file size in a configuration
+		v.Field(&req.FolderID, is.UUIDv4),
+		v.Field(&req.Tags, tagsRules...),
+		v.Field(&req.size, v.Required),
 	)
 }
 
@@ -63,6 +68,16 @@ func (app *BoxApplication) UploadSavedFile(ctx context.Context, genReq request.R
 	if acc.IdentityID != req.identityID {
 		return nil, merr.Forbidden()
 	}
+	folderID := null.NewString(req.FolderID, req.FolderID != "")
+	if err := app.mustOwnSavedFileFolder(ctx, req.identityID, folderID, "folder_id"); err != nil {
+		return nil, err
+	}
+	if err := app.mustFitInMemberFileSize(ctx, req.identityID, req.size); err != nil {
+		return nil, err
+	}
+	if err := app.mustFitInIdentityQuota(ctx, req.identityID, req.size); err != nil {
+		return nil, err
+	}
 
 	// retrieve the raw []byte from the file
 	encData, err := req.encFile.Open()
@@ -76,18 +91,13 @@ func (app *BoxApplication) UploadSavedFile(ctx context.Context, genReq request.R
 		return nil, merr.From(err).Desc("encrypted file id")
 	}
 
-	// create the encrypted file entity
+	// create the encrypted file entity and upload the encrypted data
 	eFile := files.EncryptedFile{
 		ID:   eFileID,
 		Size: req.size,
 	}
-	if err := files.Create(ctx, app.DB, eFile); err != nil {
-		return nil, merr.From(err).Desc("creating file")
-	}
-
-	// upload the encrypted data
-	if err := files.Upload(ctx, app.filesRepo, eFileID, encData); err != nil {
-		return nil, merr.From(err).Desc("uploading file")
+	if err := app.storeEncryptedFile(ctx, &eFile, encData); err != nil {
+		return nil, err
 	}
 
 	sFileID, err := uuid.NewString()
@@ -103,6 +113,8 @@ func (app *BoxApplication) UploadSavedFile(ctx context.Context, genReq request.R
 		EncryptedFileID:   eFileID,
 		EncryptedMetadata: req.EncryptedMetadata,
 		KeyFingerprint:    req.KeyFingerprint,
+		FolderID:          folderID,
+		Tags:              req.Tags,
 	}
 
 	if err := files.CreateSavedFile(ctx, app.DB, &sFile); err != nil {
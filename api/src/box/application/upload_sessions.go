@@ -0,0 +1,298 @@
+package application
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/format"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+//
+// this file contains the resumable upload of encrypted files:
+// an upload session is started, numbered chunks are put then the session is finalized,
+// creating the msg.file event only at this moment.
+
+// UploadSessionView ...
+type UploadSessionView struct {
+	ID             string    `json:"id"`
+	BoxID          string    `json:"box_id"`
+	Size           int64     `json:"size"`
+	ChunkSize      int64     `json:"chunk_size"`
+	ChunkCount     int       `json:"chunk_count"`
+	ReceivedChunks []int     `json:"received_chunks"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+func newUploadSessionView(session files.UploadSession, receivedChunks []int) UploadSessionView {
+	if receivedChunks == nil {
+		receivedChunks = []int{}
+	}
+	return UploadSessionView{
+		ID:             session.ID,
+		BoxID:          session.BoxID,
+		Size:           session.Size,
+		ChunkSize:      session.ChunkSize,
+		ChunkCount:     session.ChunkCount(),
+		ReceivedChunks: receivedChunks,
+		ExpiresAt:      session.ExpiresAt,
+	}
+}
+
+// StartUploadSessionRequest ...
+type StartUploadSessionRequest struct {
+	boxID string
+	Size  int64 `json:"size"`
+}
+
+// BindAndValidate ...
+func (req *StartUploadSessionRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	req.boxID = eCtx.Param("bid")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.Size, v.Required, v.Min(int64(1))),
+	)
+}
+
+// StartUploadSession ...
+func (app *BoxApplication) StartUploadSession(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*StartUploadSessionRequest)
+
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Unauthorized()
+	}
+	if err := app.mustBeAbleToUpload(ctx, req.boxID, acc.IdentityID); err != nil {
+		return nil, err
+	}
+	if err := app.mustAcceptBoxFile(ctx, req.boxID, req.Size); err != nil {
+		return nil, err
+	}
+
+	session, err := files.StartUploadSession(ctx, app.RedConn, app.filesRepo, req.boxID, acc.IdentityID, req.Size)
+	if err != nil {
+		return nil, merr.From(err).Desc("starting upload session")
+	}
+	return newUploadSessionView(session, nil), nil
+}
+
+// GetUploadSessionRequest ...
+type GetUploadSessionRequest struct {
+	boxID string
+	id    string
+}
+
+// BindAndValidate ...
+func (req *GetUploadSessionRequest) BindAndValidate(eCtx echo.Context) error {
+	req.boxID = eCtx.Param("bid")
+	req.id = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.id, v.Required, is.UUIDv4),
+	)
+}
+
+// GetUploadSession returns the session state, allowing to resume it
+func (app *BoxApplication) GetUploadSession(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*GetUploadSessionRequest)
+
+	session, err := app.getOwnUploadSession(ctx, req.boxID, req.id)
+	if err != nil {
+		return nil, err
+	}
+	receivedChunks, err := files.ListUploadSessionChunks(ctx, app.filesRepo, session)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing chunks")
+	}
+	return newUploadSessionView(session, receivedChunks), nil
+}
+
+// UploadChunkRequest ...
+type UploadChunkRequest struct {
+	boxID  string
+	id     string
+	number int
+	data   []byte
+}
+
+// BindAndValidate ...
+func (req *UploadChunkRequest) BindAndValidate(eCtx echo.Context) error {
+	req.boxID = eCtx.Param("bid")
+	req.id = eCtx.Param("id")
+	number, err := strconv.Atoi(eCtx.Param("number"))
+	if err != nil {
+		return merr.BadRequest().Ori(merr.OriPath).Add("number", merr.DVMalformed)
+	}
+	req.number = number
+	if err := v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.id, v.Required, is.UUIDv4),
+		v.Field(&req.number, v.Required, v.Min(1)),
+	); err != nil {
+		return err
+	}
+
+	// the raw body is the chunk - read one more byte than allowed to detect too large chunks
+	req.data, err = ioutil.ReadAll(io.LimitReader(eCtx.Request().Body, files.ChunkSize+1))
+	if err != nil {
+		return merr.BadRequest().Ori(merr.OriBody).Add("chunk", merr.DVInvalid).Desc(err.Error())
+	}
+	return nil
+}
+
+// UploadChunk ...
+func (app *BoxApplication) UploadChunk(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*UploadChunkRequest)
+
+	session, err := app.getOwnUploadSession(ctx, req.boxID, req.id)
+	if err != nil {
+		return nil, err
+	}
+	if err := files.UploadSessionChunk(ctx, app.RedConn, app.filesRepo, &session, req.number, req.data); err != nil {
+		return nil, merr.From(err).Desc("uploading chunk")
+	}
+	return nil, nil
+}
+
+// FinalizeUploadSessionRequest ...
+type FinalizeUploadSessionRequest struct {
+	boxID string
+	id    string
+
+	MsgEncContent string `json:"msg_encrypted_content"`
+	MsgPubKey     string `json:"msg_public_key"`
+	MsgTTL        *int64 `json:"msg_ttl"`
+	// MsgReferrerID is set to upload the file as a new version of the file of this msg.file
+	MsgReferrerID *string `json:"msg_referrer_id"`
+}
+
+// BindAndValidate ...
+func (req *FinalizeUploadSessionRequest) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(req); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	req.boxID = eCtx.Param("bid")
+	req.id = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.id, v.Required, is.UUIDv4),
+		v.Field(&req.MsgEncContent, v.Required, v.Match(format.UnpaddedURLSafeBase64)),
+		v.Field(&req.MsgPubKey, v.Required),
+		v.Field(&req.MsgTTL, v.Min(int64(1)), v.Max(events.MaxExpirationSeconds), v.When(req.MsgReferrerID != nil, v.Nil)),
+		v.Field(&req.MsgReferrerID, is.UUIDv4),
+	)
+}
+
+// FinalizeUploadSession assembles the chunks into the encrypted file then creates the msg.file event
+func (app *BoxApplication) FinalizeUploadSession(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*FinalizeUploadSessionRequest)
+
+	session, err := app.getOwnUploadSession(ctx, req.boxID, req.id)
+	if err != nil {
+		return nil, err
+	}
+	// the membership, the box state and the used space might have changed since the session start
+	if err := app.mustBeAbleToUpload(ctx, session.BoxID, session.IdentityID); err != nil {
+		return nil, err
+	}
+	if err := app.mustAcceptBoxFile(ctx, session.BoxID, session.Size); err != nil {
+		return nil, err
+	}
+
+	// build the event before completing the upload to not store a file with an invalid event
+	e, err := newMsgFileEvent(ctx, session.BoxID, session.IdentityID, session.EncryptedFileID, req.MsgEncContent, req.MsgPubKey, req.MsgTTL, req.MsgReferrerID)
+	if err != nil {
+		return nil, merr.From(err).Desc("creating msg file event")
+	}
+
+	if err := files.CompleteUploadSession(ctx, app.RedConn, app.filesRepo, session); err != nil {
+		return nil, merr.From(err).Desc("completing upload session")
+	}
+
+	// create the encrypted file entity
+	eFile := files.EncryptedFile{
+		ID:   session.EncryptedFileID,
+		Size: session.Size,
+	}
+	if err := app.createStoredEncryptedFile(ctx, &eFile); err != nil {
+		if delErr := app.filesRepo.Delete(ctx, session.EncryptedFileID); delErr != nil {
+			return nil, merr.From(err).Descf("deleting file: %v", delErr)
+		}
+		return nil, err
+	}
+
+	return app.createMsgFileEvent(ctx, e, session.EncryptedFileID, session.Size)
+}
+
+// AbortUploadSessionRequest ...
+type AbortUploadSessionRequest struct {
+	boxID string
+	id    string
+}
+
+// BindAndValidate ...
+func (req *AbortUploadSessionRequest) BindAndValidate(eCtx echo.Context) error {
+	req.boxID = eCtx.Param("bid")
+	req.id = eCtx.Param("id")
+	return v.ValidateStruct(req,
+		v.Field(&req.boxID, v.Required, is.UUIDv4),
+		v.Field(&req.id, v.Required, is.UUIDv4),
+	)
+}
+
+// AbortUploadSession removes the uploaded chunks
+func (app *BoxApplication) AbortUploadSession(ctx context.Context, genReq request.Request) (interface{}, error) {
+	req := genReq.(*AbortUploadSessionRequest)
+
+	session, err := app.getOwnUploadSession(ctx, req.boxID, req.id)
+	if err != nil {
+		return nil, err
+	}
+	if err := files.AbortUploadSession(ctx, app.RedConn, app.filesRepo, session); err != nil {
+		return nil, merr.From(err).Desc("aborting upload session")
+	}
+	return nil, nil
+}
+
+// mustBeAbleToUpload checks the identity can upload a file in the box
+func (app *BoxApplication) mustBeAbleToUpload(ctx context.Context, boxID, identityID string) error {
+	if err := events.MustBeMember(ctx, app.DB, app.RedConn, boxID, identityID); err != nil {
+		return err
+	}
+	if err := events.MustBoxExists(ctx, app.DB, boxID); err != nil {
+		return merr.From(err).Desc("checking exist")
+	}
+	// archived boxes are read-only
+	return events.MustNotBeArchived(ctx, app.DB, boxID)
+}
+
+// getOwnUploadSession returns the upload session only if it has been started
+// by the current identity on the given box, considering it as not found otherwise
+func (app *BoxApplication) getOwnUploadSession(ctx context.Context, boxID, id string) (files.UploadSession, error) {
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return files.UploadSession{}, merr.Unauthorized()
+	}
+	session, err := files.GetUploadSession(ctx, app.RedConn, id)
+	if err != nil {
+		return session, merr.From(err).Desc("getting upload session")
+	}
+	if session.IdentityID != acc.IdentityID || session.BoxID != boxID {
+		return session, merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	return session, nil
+}
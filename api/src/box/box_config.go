@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
 )
@@ -19,7 +20,7 @@ func initConfig() {
 	}
 	switch os.Getenv("ENV") {
 	case "production":
-		mandatoryFields = append(mandatoryFields, []string{"aws.ses_region", "aws.s3_region", "aws.encrypted_files_bucket"}...)
+		mandatoryFields = append(mandatoryFields, []string{"aws.ses_region"}...)
 		if os.Getenv("AWS_ACCESS_KEY") == "" {
 			log.Warn().Msg("AWS_ACCESS_KEY not set")
 		}
@@ -27,14 +28,23 @@ func initConfig() {
 			log.Warn().Msg("AWS_SECRET_KEY not set")
 		}
 	case "development":
-		mandatoryFields = append(mandatoryFields, []string{"server.encrypted_files"}...)
 		log.Info().Msg("{} Development mode is activated. {}")
 	default:
 		log.Fatal().Msg("unknown ENV value (should be production|development)")
 	}
+	fileSystemFields := []string{"server.encrypted_files"}
+	if viper.GetBool("direct_transfer.enabled") {
+		mandatoryFields = append(mandatoryFields, "direct_transfer.expiration")
+		fileSystemFields = append(fileSystemFields, "server.encrypted_files_url", "server.encrypted_files_signing_key")
+	}
+	mandatoryFields = append(mandatoryFields, config.StorageMandatoryFields(
+		fileSystemFields,
+		[]string{"aws.encrypted_files_bucket"},
+		[]string{"s3.encrypted_files_bucket"},
+	)...)
 
 	// no secret fields so far
-	secretFields := []string{"authflow.self_encoded_jwk"}
+	secretFields := []string{"authflow.self_encoded_jwk", "server.encrypted_files_signing_key"}
 	config.FatalIfMissing("BOX", mandatoryFields)
 	config.Print("BOX", secretFields)
 }
@@ -3,12 +3,14 @@ package box
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/go-redis/redis/v7"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/authz"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/db"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
@@ -17,7 +19,6 @@ import (
 	"gitlab.misakey.dev/misakey/backend/api/src/box/application"
 	bentrypoints "gitlab.misakey.dev/misakey/backend/api/src/box/entrypoints"
 	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
-	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/rester/http"
 )
 
@@ -60,23 +61,21 @@ func InitModule(
 		log.Fatal().Err(err).Msg("could not connect to redis")
 	}
 
-	var filesRepo files.FileStorageRepo
-	env := os.Getenv("ENV")
-	if env == "development" {
-		filesRepo = files.NewFileSystem(viper.GetString("server.encrypted_files"))
-	} else if env == "production" {
-		filesRepo = files.NewFileAmazonS3(
-			viper.GetString("aws.s3_region"),
-			viper.GetString("aws.encrypted_files_bucket"),
-		)
-	} else {
-		log.Fatal().Msg("unknown ENV value (should be production|development)")
+	filesRepo, err := NewFileStorageRepo()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init files storage")
 	}
 
 	// assign self client id to a variable since used many times
 	selfCliID := viper.GetString("authflow.self_client_id")
 
-	boxService := application.NewBoxApplication(boxDBConn, ssoDBConn, redConn, filesRepo, selfCliID, identityRepo, cryptoRepo)
+	// direct transfers to the storage are optional
+	var directTransferTTL time.Duration
+	if viper.GetBool("direct_transfer.enabled") {
+		directTransferTTL = viper.GetDuration("direct_transfer.expiration")
+	}
+
+	boxService := application.NewBoxApplication(boxDBConn, ssoDBConn, redConn, filesRepo, selfCliID, directTransferTTL, viper.GetBool("storage.deduplication"), config.GetUploadLimits(), identityRepo, cryptoRepo)
 	wsHandler := bentrypoints.NewWebsocketHandler(viper.GetStringSlice("websockets.allowed_origins"), &boxService)
 
 	adminHydraFORM := http.NewClient(
@@ -26,6 +26,12 @@ func bindRoutes(
 		app.CreateBox,
 		request.ResponseCreated,
 	))
+	boxPath.POST(selfOIDCHandlerFactory.NewACR2(
+		"/import",
+		func() request.Request { return &application.ImportBoxRequest{} },
+		app.ImportBox,
+		request.ResponseCreated,
+	))
 	boxPath.GET(selfOIDCHandlerFactory.NewACR1(
 		"/:id",
 		func() request.Request { return &application.GetBoxRequest{} },
@@ -60,6 +66,12 @@ func bindRoutes(
 		app.ListBoxMembers,
 		request.ResponseOK,
 	))
+	boxPath.GET(selfOIDCHandlerFactory.NewACR2(
+		"/:id/export",
+		func() request.Request { return &application.ExportBoxRequest{} },
+		app.ExportBox,
+		request.ResponseStream,
+	))
 	boxPath.DELETE(selfOIDCHandlerFactory.NewACR2(
 		"/:id",
 		func() request.Request { return &application.DeleteBoxRequest{} },
@@ -81,6 +93,18 @@ func bindRoutes(
 		app.GetOrgBox,
 		request.ResponseOK,
 	))
+	orgPath.GET(anyOIDCHandlerFactory.NewACR2(
+		"/:oid/storage-quota",
+		func() request.Request { return &application.OrgStorageRequest{} },
+		app.ListOrgStorageQuota,
+		request.ResponseOK,
+	))
+	orgPath.GET(anyOIDCHandlerFactory.NewACR2(
+		"/:oid/used-space",
+		func() request.Request { return &application.OrgStorageRequest{} },
+		app.GetOrgUsedSpace,
+		request.ResponseOK,
+	))
 
 	// ----------------------
 	// Access related routes
@@ -107,6 +131,13 @@ func bindRoutes(
 		request.ResponseOK,
 	))
 
+	boxPath.GET(selfOIDCHandlerFactory.NewACR1(
+		"/:id/files/:eid/versions",
+		func() request.Request { return &application.ListFileVersionsRequest{} },
+		app.ListFileVersions,
+		request.ResponseOK,
+	))
+
 	boxPath.HEAD(selfOIDCHandlerFactory.NewACR1(
 		"/:id/files",
 		func() request.Request { return &application.CountBoxFilesRequest{} },
@@ -146,6 +177,48 @@ func bindRoutes(
 		app.UploadEncryptedFile,
 		request.ResponseCreated,
 	))
+	boxPath.POST(anyOIDCHandlerFactory.NewACR1(
+		"/:bid/encrypted-files/direct-uploads",
+		func() request.Request { return &application.StartDirectUploadRequest{} },
+		app.StartDirectUpload,
+		request.ResponseCreated,
+	))
+	boxPath.POST(anyOIDCHandlerFactory.NewACR1(
+		"/:bid/encrypted-files/direct-uploads/:id/confirm",
+		func() request.Request { return &application.ConfirmDirectUploadRequest{} },
+		app.ConfirmDirectUpload,
+		request.ResponseCreated,
+	))
+	boxPath.POST(anyOIDCHandlerFactory.NewACR1(
+		"/:bid/encrypted-files/uploads",
+		func() request.Request { return &application.StartUploadSessionRequest{} },
+		app.StartUploadSession,
+		request.ResponseCreated,
+	))
+	boxPath.GET(anyOIDCHandlerFactory.NewACR1(
+		"/:bid/encrypted-files/uploads/:id",
+		func() request.Request { return &application.GetUploadSessionRequest{} },
+		app.GetUploadSession,
+		request.ResponseOK,
+	))
+	boxPath.PUT(anyOIDCHandlerFactory.NewACR1(
+		"/:bid/encrypted-files/uploads/:id/chunks/:number",
+		func() request.Request { return &application.UploadChunkRequest{} },
+		app.UploadChunk,
+		request.ResponseNoContent,
+	))
+	boxPath.POST(anyOIDCHandlerFactory.NewACR1(
+		"/:bid/encrypted-files/uploads/:id/finalize",
+		func() request.Request { return &application.FinalizeUploadSessionRequest{} },
+		app.FinalizeUploadSession,
+		request.ResponseCreated,
+	))
+	boxPath.DELETE(anyOIDCHandlerFactory.NewACR1(
+		"/:bid/encrypted-files/uploads/:id",
+		func() request.Request { return &application.AbortUploadSessionRequest{} },
+		app.AbortUploadSession,
+		request.ResponseNoContent,
+	))
 	boxPath.PUT(selfOIDCHandlerFactory.NewACR1(
 		"/:id/new-events-count/ack",
 		func() request.Request { return &application.AckNewEventsCountRequest{} },
@@ -209,6 +282,12 @@ func bindRoutes(
 		app.CreateSavedFile,
 		request.ResponseCreated,
 	))
+	savedFilePath.PATCH(selfOIDCHandlerFactory.NewACR2(
+		"/:id",
+		func() request.Request { return &application.UpdateSavedFileRequest{} },
+		app.UpdateSavedFile,
+		request.ResponseOK,
+	))
 	savedFilePath.DELETE(selfOIDCHandlerFactory.NewACR2(
 		"/:id",
 		func() request.Request { return &application.DeleteSavedFileRequest{} },
@@ -232,6 +311,34 @@ func bindRoutes(
 		},
 	))
 
+	// ----------------------
+	// Saved File Folders related routes
+	savedFileFolderPath := router.Group("/saved-file-folders")
+	savedFileFolderPath.POST(selfOIDCHandlerFactory.NewACR2(
+		"",
+		func() request.Request { return &application.CreateSavedFileFolderRequest{} },
+		app.CreateSavedFileFolder,
+		request.ResponseCreated,
+	))
+	savedFileFolderPath.GET(selfOIDCHandlerFactory.NewACR2(
+		"",
+		func() request.Request { return &application.ListSavedFileFoldersRequest{} },
+		app.ListSavedFileFolders,
+		request.ResponseOK,
+	))
+	savedFileFolderPath.PATCH(selfOIDCHandlerFactory.NewACR2(
+		"/:id",
+		func() request.Request { return &application.UpdateSavedFileFolderRequest{} },
+		app.UpdateSavedFileFolder,
+		request.ResponseOK,
+	))
+	savedFileFolderPath.DELETE(selfOIDCHandlerFactory.NewACR2(
+		"/:id",
+		func() request.Request { return &application.DeleteSavedFileFolderRequest{} },
+		app.DeleteSavedFileFolder,
+		request.ResponseNoContent,
+	))
+
 	// ----------------------
 	// Encrypted Files related routes
 	encryptedFilePath := router.Group("/encrypted-files")
@@ -239,6 +346,25 @@ func bindRoutes(
 		"/:id",
 		func() request.Request { return &application.DownloadEncryptedFileRequest{} },
 		app.DownloadEncryptedFile,
+		request.ResponseRangedStream,
+	))
+	encryptedFilePath.GET(selfOIDCHandlerFactory.NewACR1(
+		"/:id/download-url",
+		func() request.Request { return &application.GetEncryptedFileURLRequest{} },
+		app.GetEncryptedFileURL,
+		request.ResponseOK,
+	))
+	// signed urls of the file system storage are authorized by their signature
+	encryptedFilePath.PUT(selfOIDCHandlerFactory.NewPublic(
+		"/:id/signed",
+		func() request.Request { return &application.SignedEncryptedFileRequest{} },
+		app.UploadSignedEncryptedFile,
+		request.ResponseNoContent,
+	))
+	encryptedFilePath.GET(selfOIDCHandlerFactory.NewPublic(
+		"/:id/signed",
+		func() request.Request { return &application.SignedEncryptedFileRequest{} },
+		app.DownloadSignedEncryptedFile,
 		request.ResponseStream,
 	))
 
@@ -0,0 +1,30 @@
+package box
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// NewFileStorageRepo returns the storage of encrypted files for the configured backend,
+// shared by the box module and the jobs
+func NewFileStorageRepo() (files.FileStorageRepo, error) {
+	switch backend := config.StorageBackend(); backend {
+	case config.FileSystemStorage:
+		return files.NewFileSystem(
+			viper.GetString("server.encrypted_files"),
+			viper.GetString("server.encrypted_files_url"),
+			viper.GetString("server.encrypted_files_signing_key"),
+		), nil
+	case config.AmazonS3Storage:
+		return files.NewFileAmazonS3(config.S3Config(), viper.GetString("aws.encrypted_files_bucket")), nil
+	case config.S3CompatibleStorage:
+		return files.NewFileAmazonS3(config.S3Config(), viper.GetString("s3.encrypted_files_bucket")), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
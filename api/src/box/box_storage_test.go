@@ -0,0 +1,47 @@
+package box
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+func TestNewFileStorageRepo(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("s3.region", "fr-par")
+	viper.Set("s3.endpoint", "https://s3.fr-par.scw.cloud")
+
+	tests := map[string]struct {
+		backend      string
+		expectedRepo files.FileStorageRepo
+		expectedErr  bool
+	}{
+		"file system": {
+			backend:      "filesystem",
+			expectedRepo: &files.FileSystem{},
+		},
+		"s3 compatible": {
+			backend:      "s3_compatible",
+			expectedRepo: &files.FileAmazonS3{},
+		},
+		"unknown backend": {
+			backend:     "floppy",
+			expectedErr: true,
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			viper.Set("storage.backend", test.backend)
+			repo, err := NewFileStorageRepo()
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.IsType(t, test.expectedRepo, repo)
+		})
+	}
+}
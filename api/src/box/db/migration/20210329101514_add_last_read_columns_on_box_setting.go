@@ -0,0 +1,29 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func initAddLastReadColumnsOnBoxSetting() {
+	goose.AddMigration(upAddLastReadColumnsOnBoxSetting, downAddLastReadColumnsOnBoxSetting)
+}
+
+func upAddLastReadColumnsOnBoxSetting(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE box_setting
+			ADD COLUMN last_read_event_id UUID,
+			ADD COLUMN last_read_at timestamptz;
+	`)
+	return err
+}
+
+func downAddLastReadColumnsOnBoxSetting(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE box_setting
+			DROP COLUMN last_read_event_id,
+			DROP COLUMN last_read_at;
+	`)
+	return err
+}
@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func initAddContentHashOnEncryptedFile() {
+	goose.AddMigration(upAddContentHashOnEncryptedFile, downAddContentHashOnEncryptedFile)
+}
+
+func upAddContentHashOnEncryptedFile(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE encrypted_file
+			ADD COLUMN storage_key UUID,
+			ADD COLUMN content_hash VARCHAR(64);
+		UPDATE encrypted_file SET storage_key = id;
+		ALTER TABLE encrypted_file ALTER COLUMN storage_key SET NOT NULL;
+		CREATE INDEX encrypted_file_storage_key_idx ON encrypted_file(storage_key);
+		CREATE INDEX encrypted_file_content_hash_idx ON encrypted_file(content_hash, size);
+	`)
+	return err
+}
+
+func downAddContentHashOnEncryptedFile(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		DROP INDEX encrypted_file_content_hash_idx;
+		DROP INDEX encrypted_file_storage_key_idx;
+		ALTER TABLE encrypted_file
+			DROP COLUMN storage_key,
+			DROP COLUMN content_hash;
+	`)
+	return err
+}
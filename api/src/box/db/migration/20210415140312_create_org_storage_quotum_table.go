@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func initCreateOrgStorageQuotumTable() {
+	goose.AddMigration(upCreateOrgStorageQuotumTable, downCreateOrgStorageQuotumTable)
+}
+
+func upCreateOrgStorageQuotumTable(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE org_storage_quotum(
+			id UUID PRIMARY KEY,
+			org_id UUID NOT NULL,
+			value BIGINT NOT NULL,
+			origin VARCHAR(255) NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX org_storage_quotum_org_id_idx ON org_storage_quotum(org_id);
+	`)
+	return err
+}
+
+func downCreateOrgStorageQuotumTable(tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE org_storage_quotum;`)
+	return err
+}
@@ -0,0 +1,40 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func initAddSavedFileFoldersAndTags() {
+	goose.AddMigration(upAddSavedFileFoldersAndTags, downAddSavedFileFoldersAndTags)
+}
+
+func upAddSavedFileFoldersAndTags(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE saved_file_folder(
+			id UUID PRIMARY KEY,
+			identity_id UUID NOT NULL,
+			parent_id UUID REFERENCES saved_file_folder NULL,
+			encrypted_name TEXT NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX saved_file_folder_identity_id_idx ON saved_file_folder(identity_id);
+		CREATE INDEX saved_file_folder_parent_id_idx ON saved_file_folder(parent_id);
+
+		ALTER TABLE saved_file
+			ADD COLUMN folder_id UUID REFERENCES saved_file_folder NULL,
+			ADD COLUMN tags VARCHAR(255)[] NOT NULL DEFAULT '{}';
+		CREATE INDEX saved_file_folder_id_idx ON saved_file(folder_id);
+		CREATE INDEX saved_file_tags_idx ON saved_file USING GIN(tags);
+	`)
+	return err
+}
+
+func downAddSavedFileFoldersAndTags(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE saved_file DROP COLUMN folder_id, DROP COLUMN tags;
+		DROP TABLE saved_file_folder;
+	`)
+	return err
+}
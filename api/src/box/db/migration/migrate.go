@@ -23,6 +23,10 @@ func Launch() {
 	initCreateBoxSettingsTable()
 	initAddEncryptedInvitationKeyShare()
 	initResizeCryptoColumns()
+	initAddLastReadColumnsOnBoxSetting()
+	initAddContentHashOnEncryptedFile()
+	initCreateOrgStorageQuotumTable()
+	initAddSavedFileFoldersAndTags()
 
 	db.StartMigration(os.Getenv("DSN_BOX"), os.Getenv("MIGRATION_DIR_BOX"))
 }
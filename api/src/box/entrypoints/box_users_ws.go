@@ -1,8 +1,10 @@
 package entrypoints
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	v "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -12,6 +14,7 @@ import (
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/realtime"
 )
 
 // BoxUsersWS ...
@@ -30,6 +33,10 @@ func (wh WebsocketHandler) BoxUsersWS(c echo.Context) error {
 		return merr.Forbidden()
 	}
 
+	// track the presence of the identity while the websocket is open
+	stopPresence := wh.trackPresence(c.Request().Context(), identityID)
+	defer stopPresence()
+
 	return wh.RedisListener(
 		c,
 		fmt.Sprintf("user_%s", acc.IdentityID),
@@ -44,10 +51,65 @@ type WSMessage struct {
 	Object json.RawMessage `json:"object"`
 }
 
+// trackPresence marks the identity as online and keeps its presence alive
+// until the returned function is called.
+// Co-members are notified when the identity goes online and offline.
+func (wh WebsocketHandler) trackPresence(ctx context.Context, identityID string) func() {
+	redConn := wh.boxService.RedConn
+	online, err := realtime.Connect(redConn, identityID)
+	if err != nil {
+		logger.FromCtx(ctx).Error().Err(err).Msg("could not set presence")
+	}
+	if online {
+		if err := events.NotifyPresence(ctx, wh.boxService.DB, redConn, identityID, true); err != nil {
+			logger.FromCtx(ctx).Error().Err(err).Msg("could not notify online presence")
+		}
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(realtime.PresenceRefreshPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := realtime.RefreshPresence(redConn, identityID); err != nil {
+					logger.FromCtx(ctx).Warn().Err(err).Msg("could not refresh presence")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		// the request context might be already cancelled once the websocket is closed
+		// so a fresh context holding the logger is used
+		offCtx := logger.SetLogger(context.Background(), logger.FromCtx(ctx))
+		offline, err := realtime.Disconnect(redConn, identityID)
+		if err != nil {
+			logger.FromCtx(offCtx).Error().Err(err).Msg("could not unset presence")
+			return
+		}
+		if offline {
+			if err := events.NotifyPresence(offCtx, wh.boxService.DB, redConn, identityID, false); err != nil {
+				logger.FromCtx(offCtx).Error().Err(err).Msg("could not notify offline presence")
+			}
+		}
+	}
+}
+
 // AckObject ...
 type AckObject struct {
-	SenderID string `json:"sender_id"`
-	BoxID    string `json:"box_id"`
+	SenderID        string  `json:"sender_id"`
+	BoxID           string  `json:"box_id"`
+	LastReadEventID *string `json:"last_read_event_id"`
+}
+
+// TypingObject ...
+type TypingObject struct {
+	BoxID string `json:"box_id"`
 }
 
 func boxUsersHandler(c echo.Context, wh WebsocketHandler, receivedMsg []byte) error {
@@ -66,6 +128,13 @@ func boxUsersHandler(c echo.Context, wh WebsocketHandler, receivedMsg []byte) er
 		if err := events.DelDigestCount(c.Request().Context(), wh.boxService.RedConn, obj.SenderID, obj.BoxID); err != nil {
 			logger.FromCtx(c.Request().Context()).Error().Err(err).Msg("could not remove digestCount key")
 		}
+		// only the connected identity can mark events as read for themself
+		acc := oidc.GetAccesses(c.Request().Context())
+		if acc != nil && acc.IdentityID == obj.SenderID {
+			if err := events.MarkAsRead(c.Request().Context(), wh.boxService.DB, wh.boxService.RedConn, obj.SenderID, obj.BoxID, obj.LastReadEventID); err != nil {
+				logger.FromCtx(c.Request().Context()).Error().Err(err).Msg("could not mark events as read")
+			}
+		}
 		// resend the ack event
 		// to make sure all user websockets get it
 		// and ignore error
@@ -74,5 +143,21 @@ func boxUsersHandler(c echo.Context, wh WebsocketHandler, receivedMsg []byte) er
 			receivedMsg,
 		)
 	}
+	if message.Type == "typing" {
+		obj := TypingObject{}
+		if err := json.Unmarshal(message.Object, &obj); err != nil {
+			return err
+		}
+		if err := v.Validate(obj.BoxID, v.Required, is.UUIDv4); err != nil {
+			return merr.BadRequest().Add("box_id", merr.DVMalformed)
+		}
+		acc := oidc.GetAccesses(c.Request().Context())
+		if acc == nil {
+			return merr.Unauthorized()
+		}
+		if err := events.NotifyTyping(c.Request().Context(), wh.boxService.DB, wh.boxService.RedConn, acc.IdentityID, obj.BoxID); err != nil {
+			return merr.From(err).Desc("notifying typing")
+		}
+	}
 	return nil
 }
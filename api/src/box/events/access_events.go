@@ -51,6 +51,11 @@ func doAddAccess(
 		return nil, merr.From(err).Desc("checking admin")
 	}
 
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
 	// check content format
 	var access accessAddContent
 	if err := e.JSONContent.Unmarshal(&access); err != nil {
@@ -122,6 +127,11 @@ func doRmAccess(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExe
 		return nil, merr.From(err).Desc("checking admin")
 	}
 
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
 	if err := v.ValidateStruct(e,
 		v.Field(&e.ReferrerID, v.Required, is.UUIDv4),
 	); err != nil {
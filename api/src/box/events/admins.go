@@ -10,14 +10,65 @@ import (
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 )
 
+// adminIDs is the ordered list of the identities being admins of a box
+type adminIDs []string
+
+func (ids adminIDs) has(identityID string) bool {
+	for _, id := range ids {
+		if id == identityID {
+			return true
+		}
+	}
+	return false
+}
+
+// play the event on the list of admins:
+// the creator is the first admin then members can be promoted or demoted by admins.
+// admins leaving the box or being kicked from it lose their role.
+func (ids *adminIDs) play(e Event) error {
+	switch e.Type {
+	case etype.Create:
+		if !ids.has(e.SenderID) {
+			*ids = append(*ids, e.SenderID)
+		}
+	case etype.Memberpromote:
+		c := MemberRoleContent{}
+		if err := c.Unmarshal(e.JSONContent); err != nil {
+			return err
+		}
+		if !ids.has(c.IdentityID) {
+			*ids = append(*ids, c.IdentityID)
+		}
+	case etype.Memberdemote:
+		c := MemberRoleContent{}
+		if err := c.Unmarshal(e.JSONContent); err != nil {
+			return err
+		}
+		ids.remove(c.IdentityID)
+	case etype.Memberleave, etype.Memberkick:
+		ids.remove(e.SenderID)
+	}
+	return nil
+}
+
+func (ids *adminIDs) remove(identityID string) {
+	remaining := (*ids)[:0]
+	for _, id := range *ids {
+		if id != identityID {
+			remaining = append(remaining, id)
+		}
+	}
+	*ids = remaining
+}
+
 // MustBeAdmin ...
 func MustBeAdmin(ctx context.Context, exec boil.ContextExecutor, boxID, senderID string) error {
-	isCreator, err := isCreator(ctx, exec, boxID, senderID)
+	ids, err := ListAdminIDs(ctx, exec, boxID)
 	if err != nil {
 		return err
 	}
-	if !isCreator {
-		return merr.Forbidden().Desc("not the creator")
+	if !adminIDs(ids).has(senderID) {
+		return merr.Forbidden().Desc("not an admin")
 	}
 	return nil
 }
@@ -33,15 +84,22 @@ func IsAdmin(ctx context.Context, exec boil.ContextExecutor, boxID, senderID str
 	return (err == nil), err
 }
 
-// GetAdminID ...
-func GetAdminID(ctx context.Context, exec boil.ContextExecutor, boxID string) (string, error) {
-	createEvent, err := get(ctx, exec, eventFilters{
-		eType: null.StringFrom(etype.Create),
-		boxID: null.StringFrom(boxID),
+// ListAdminIDs of the box by replaying the events defining admins
+func ListAdminIDs(ctx context.Context, exec boil.ContextExecutor, boxID string) ([]string, error) {
+	adminEvents, err := list(ctx, exec, eventFilters{
+		boxID:  null.StringFrom(boxID),
+		eTypes: etype.DefineAdmins,
 	})
 	if err != nil {
-		return "", err
+		return nil, merr.From(err).Desc("listing admin events")
 	}
 
-	return createEvent.SenderID, nil
+	// replay events from the oldest to the most recent one
+	ids := adminIDs{}
+	for i := len(adminEvents) - 1; i >= 0; i-- {
+		if err := ids.play(adminEvents[i]); err != nil {
+			return nil, merr.From(err).Descf("playing event %s", adminEvents[i].ID)
+		}
+	}
+	return ids, nil
 }
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+func TestAdminIDsPlay(t *testing.T) {
+	newRoleEvent := func(eType, identityID string) Event {
+		var json types.JSON
+		err := json.Marshal(MemberRoleContent{IdentityID: identityID})
+		assert.Nil(t, err)
+		return Event{Type: eType, JSONContent: json, SenderID: "creator-A"}
+	}
+	create := Event{Type: "create", SenderID: "creator-A"}
+
+	t.Run("the creator is the first admin", func(t *testing.T) {
+		ids := adminIDs{}
+		assert.Nil(t, ids.play(create))
+		assert.Equal(t, adminIDs{"creator-A"}, ids)
+		assert.True(t, ids.has("creator-A"))
+		assert.False(t, ids.has("member-B"))
+	})
+
+	t.Run("members are promoted and demoted", func(t *testing.T) {
+		ids := adminIDs{}
+		for _, e := range []Event{
+			create,
+			newRoleEvent("member.promote", "member-B"),
+			newRoleEvent("member.promote", "member-C"),
+			newRoleEvent("member.promote", "member-B"),
+			newRoleEvent("member.demote", "creator-A"),
+		} {
+			assert.Nil(t, ids.play(e))
+		}
+		assert.Equal(t, adminIDs{"member-B", "member-C"}, ids)
+	})
+
+	t.Run("admins leaving or kicked lose their role", func(t *testing.T) {
+		ids := adminIDs{}
+		for _, e := range []Event{
+			create,
+			newRoleEvent("member.promote", "member-B"),
+			newRoleEvent("member.promote", "member-C"),
+			{Type: "member.leave", SenderID: "creator-A"},
+			{Type: "member.kick", SenderID: "member-C"},
+		} {
+			assert.Nil(t, ids.play(e))
+		}
+		assert.Equal(t, adminIDs{"member-B"}, ids)
+	})
+}
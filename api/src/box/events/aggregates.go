@@ -14,8 +14,8 @@ import (
 
 // BuildAggregate of an event by modifying the received pointer value
 func BuildAggregate(ctx context.Context, exec boil.ContextExecutor, e *Event) error {
-	// only msg.text and msg.file events can be aggregates
-	if e.Type == etype.Msgtext || e.Type == etype.Msgfile {
+	// only msg.text, msg.file and msg.reply events can be aggregates
+	if e.Type == etype.Msgtext || e.Type == etype.Msgfile || e.Type == etype.Msgreply {
 		msg, err := buildMessage(ctx, exec, e.ID)
 		if err != nil {
 			return merr.From(err).Descf("building message %s", e.ID)
@@ -45,6 +45,36 @@ func BuildAggregate(ctx context.Context, exec boil.ContextExecutor, e *Event) er
 				return merr.From(err).Descf("marshalling %s content", e.Type)
 			}
 		}
+
+		// reactions and replies are only shown on non-deleted messages
+		if msg.DeletedAt.IsZero() {
+			if err := aggregateInteractions(e, msg); err != nil {
+				return merr.From(err).Descf("aggregating interactions on %s", e.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// aggregateInteractions of members (reactions, replies) into the message content
+func aggregateInteractions(e *Event, msg Message) error {
+	if len(msg.Reactions) == 0 && len(msg.ReplyIDs) == 0 {
+		return nil
+	}
+
+	// the content is handled as a generic map since the shape depends on the message type
+	var content map[string]interface{}
+	if err := json.Unmarshal(e.JSONContent, &content); err != nil {
+		return merr.From(err).Descf("unmarshaling %s json", e.Type)
+	}
+	if len(msg.Reactions) > 0 {
+		content["reactions"] = msg.Reactions
+	}
+	if len(msg.ReplyIDs) > 0 {
+		content["reply_ids"] = msg.ReplyIDs
+	}
+	if err := e.JSONContent.Marshal(content); err != nil {
+		return merr.From(err).Descf("marshalling %s content", e.Type)
 	}
 	return nil
 }
@@ -107,10 +107,14 @@ func sendRealtimeUpdate(ctx context.Context, e *Event, exec boil.ContextExecutor
 	if err != nil {
 		return merr.From(err).Desc("getting creator")
 	}
+	admins, err := ListAdminIDs(ctx, exec, e.BoxID)
+	if err != nil {
+		return merr.From(err).Desc("listing admins")
+	}
 
 	for memberID := range uniqRecipientsIDs {
 		object := formattedEvent
-		if createInfo.CreatorID == memberID {
+		if adminIDs(admins).has(memberID) {
 			object = transparentFormattedEvent
 		}
 		bu := realtime.Update{
@@ -159,14 +159,14 @@ func BuildIdentityOrgBoxCache(
 	// 2. to build the cache means to build the list of user's boxes for all organizations
 	// the user's boxes are defined by:
 	// - what they have joined (a)
-	// - what they have created (b)
+	// - what they have created and not left (b)
 	// a.
 	activeJoins, err := ListIdentityActiveJoins(ctx, exec, identityID)
 	if err != nil {
 		return boxIDsByOrgID, merr.From(err).Desc("listing joined box ids")
 	}
 	// b.
-	creates, err := ListCreateByCreatorID(ctx, exec, identityID)
+	creates, err := ListActiveCreateByCreatorID(ctx, exec, identityID)
 	if err != nil {
 		return boxIDsByOrgID, merr.From(err).Desc("listing creator box ids")
 	}
@@ -23,7 +23,11 @@ type Box struct {
 	DataSubject *string     `json:"data_subject,omitempty"`
 	PublicKey   string      `json:"public_key"`
 	Title       string      `json:"title"`
+	Description string      `json:"description"`
 	AccessMode  string      `json:"access_mode"`
+	AdminIDs    []string    `json:"admin_ids"`
+	Retention   int64       `json:"retention"`
+	Archived    bool        `json:"archived"`
 
 	// internal computation logic
 	creatorID string
@@ -34,6 +38,7 @@ type computer struct {
 	ePlayer map[string]func(context.Context, Event) error
 
 	events []Event
+	admins adminIDs
 
 	exec           boil.ContextExecutor
 	identityMapper *IdentityMapper
@@ -42,15 +47,8 @@ type computer struct {
 	box Box
 }
 
-// computeBox box according to the received boxID.
-// The function retrieves events linked to the boxID using received db connector.
-func computeBox(
-	ctx context.Context,
-	boxID string,
-	exec boil.ContextExecutor,
-	identityMapper *IdentityMapper,
-) (Box, error) {
-	// init the computer system
+// newComputer inits the computer system with all the event players
+func newComputer(boxID string, exec boil.ContextExecutor, identityMapper *IdentityMapper) *computer {
 	computer := &computer{
 		exec:           exec,
 		identityMapper: identityMapper,
@@ -59,9 +57,29 @@ func computeBox(
 	computer.ePlayer = map[string]func(context.Context, Event) error{
 		// NOTE: to add an new event here should involve attention on the RequireToBuild method
 		// used to retrieve events to compute the box
-		etype.Create:          computer.playCreate,
-		etype.Stateaccessmode: computer.playStateAccessMode,
+		etype.Create:           computer.playCreate,
+		etype.Stateaccessmode:  computer.playStateAccessMode,
+		etype.Statetitle:       computer.playStateTitle,
+		etype.Statedescription: computer.playStateDescription,
+		etype.Stateretention:   computer.playStateRetention,
+		etype.Statearchived:    computer.playStateArchived,
+		etype.Memberpromote:    computer.playMemberRole,
+		etype.Memberdemote:     computer.playMemberRole,
+		etype.Memberleave:      computer.playMemberRole,
+		etype.Memberkick:       computer.playMemberRole,
 	}
+	return computer
+}
+
+// computeBox box according to the received boxID.
+// The function retrieves events linked to the boxID using received db connector.
+func computeBox(
+	ctx context.Context,
+	boxID string,
+	exec boil.ContextExecutor,
+	identityMapper *IdentityMapper,
+) (Box, error) {
+	computer := newComputer(boxID, exec, identityMapper)
 
 	// automatically retrieve events if 0 events loaded
 	var err error
@@ -117,9 +135,8 @@ func computeBoxView(
 
 	// creator view
 	// NOTE: need transparency on creator email if the connected user is an admin
-	// NOTE: must change implementing advanced admin role
 	acc := oidc.GetAccesses(ctx)
-	isAdmin := (acc != nil && acc.IdentityID == box.creatorID)
+	isAdmin := (acc != nil && adminIDs(box.AdminIDs).has(acc.IdentityID))
 	view.Creator, err = identityMapper.Get(ctx, box.creatorID, isAdmin)
 	if err != nil {
 		return view, merr.From(err).Desc("retrieving creator")
@@ -189,6 +206,7 @@ func (c *computer) do(ctx context.Context) error {
 			return merr.From(err).Descf("playing event %s", e.ID)
 		}
 	}
+	c.box.AdminIDs = c.admins
 	return nil
 }
 
@@ -219,6 +237,10 @@ func (c *computer) playCreate(ctx context.Context, e Event) error {
 
 	// save the creator id for future logic - data obfuscation
 	c.box.creatorID = e.SenderID
+	// the creator is the first admin of the box
+	if err := c.admins.play(e); err != nil {
+		return err
+	}
 
 	// compute data subject identifier value
 	if creationContent.SubjectIdentityID != nil {
@@ -240,3 +262,43 @@ func (c *computer) playStateAccessMode(_ context.Context, e Event) error {
 	c.box.AccessMode = accessModeContent.Value
 	return nil
 }
+
+func (c *computer) playStateTitle(_ context.Context, e Event) error {
+	titleContent := TitleContent{}
+	if err := titleContent.Unmarshal(e.JSONContent); err != nil {
+		return err
+	}
+	c.box.Title = titleContent.Value
+	return nil
+}
+
+func (c *computer) playStateDescription(_ context.Context, e Event) error {
+	descriptionContent := DescriptionContent{}
+	if err := descriptionContent.Unmarshal(e.JSONContent); err != nil {
+		return err
+	}
+	c.box.Description = descriptionContent.Value
+	return nil
+}
+
+func (c *computer) playStateRetention(_ context.Context, e Event) error {
+	retentionContent := RetentionContent{}
+	if err := retentionContent.Unmarshal(e.JSONContent); err != nil {
+		return err
+	}
+	c.box.Retention = retentionContent.Value
+	return nil
+}
+
+func (c *computer) playStateArchived(_ context.Context, e Event) error {
+	archivedContent := ArchivedContent{}
+	if err := archivedContent.Unmarshal(e.JSONContent); err != nil {
+		return err
+	}
+	c.box.Archived = archivedContent.Value.Bool
+	return nil
+}
+
+func (c *computer) playMemberRole(_ context.Context, e Event) error {
+	return c.admins.play(e)
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+func TestComputeBox(t *testing.T) {
+	newEvent := func(eType string, content interface{}, createdAt time.Time) Event {
+		var json types.JSON
+		err := json.Marshal(content)
+		assert.Nil(t, err)
+		return Event{
+			Type:        eType,
+			JSONContent: json,
+			SenderID:    "creator-A",
+			CreatedAt:   createdAt,
+		}
+	}
+	now := time.Now()
+	create := newEvent("create", CreationContent{Title: "initial title", OwnerOrgID: "org-A"}, now)
+
+	t.Run("the title is the one set on creation", func(t *testing.T) {
+		c := newComputer("box-A", nil, nil)
+		c.events = []Event{create}
+		err := c.do(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, "initial title", c.box.Title)
+		assert.Equal(t, "", c.box.Description)
+	})
+
+	t.Run("state events override the title and the description", func(t *testing.T) {
+		c := newComputer("box-A", nil, nil)
+		// events are listed from the most recent to the oldest one
+		c.events = []Event{
+			newEvent("state.title", TitleContent{Value: "last title"}, now.Add(3*time.Second)),
+			newEvent("state.description", DescriptionContent{Value: "a description"}, now.Add(2*time.Second)),
+			newEvent("state.title", TitleContent{Value: "first rename"}, now.Add(time.Second)),
+			create,
+		}
+		err := c.do(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, "last title", c.box.Title)
+		assert.Equal(t, "a description", c.box.Description)
+		assert.Equal(t, "org-A", c.box.OwnerOrgID)
+	})
+
+	t.Run("the last retention event sets the retention of the box", func(t *testing.T) {
+		c := newComputer("box-A", nil, nil)
+		c.events = []Event{
+			newEvent("state.retention", RetentionContent{Value: 3600}, now.Add(2*time.Second)),
+			newEvent("state.retention", RetentionContent{Value: 60}, now.Add(time.Second)),
+			create,
+		}
+		err := c.do(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3600), c.box.Retention)
+	})
+
+	t.Run("the box can be archived then unarchived", func(t *testing.T) {
+		c := newComputer("box-A", nil, nil)
+		c.events = []Event{
+			newEvent("state.archived", ArchivedContent{Value: null.BoolFrom(true)}, now.Add(time.Second)),
+			create,
+		}
+		err := c.do(context.Background())
+		assert.Nil(t, err)
+		assert.True(t, c.box.Archived)
+
+		c = newComputer("box-A", nil, nil)
+		c.events = []Event{
+			newEvent("state.archived", ArchivedContent{Value: null.BoolFrom(false)}, now.Add(2*time.Second)),
+			newEvent("state.archived", ArchivedContent{Value: null.BoolFrom(true)}, now.Add(time.Second)),
+			create,
+		}
+		err = c.do(context.Background())
+		assert.Nil(t, err)
+		assert.False(t, c.box.Archived)
+	})
+}
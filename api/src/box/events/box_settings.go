@@ -30,7 +30,9 @@ func UpdateBoxSetting(ctx context.Context, exec boil.ContextExecutor, boxSetting
 		BoxID:      boxSetting.BoxID,
 		Muted:      boxSetting.Muted,
 	}
-	return toUpsert.Upsert(ctx, exec, true, []string{sqlboiler.BoxSettingColumns.BoxID, sqlboiler.BoxSettingColumns.IdentityID}, boil.Infer(), boil.Infer())
+	// only settings columns are updated to not override read receipts
+	updateColumns := boil.Whitelist(sqlboiler.BoxSettingColumns.Muted, sqlboiler.BoxSettingColumns.UpdatedAt)
+	return toUpsert.Upsert(ctx, exec, true, []string{sqlboiler.BoxSettingColumns.BoxID, sqlboiler.BoxSettingColumns.IdentityID}, updateColumns, boil.Infer())
 }
 
 // GetBoxSettings
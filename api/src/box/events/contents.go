@@ -36,12 +36,20 @@ type anyContent interface {
 }
 
 var contentTypeGetters = map[string]func() anyContent{
-	etype.Accessadd:       func() anyContent { return &accessAddContent{} },
-	etype.Create:          func() anyContent { return &CreationContent{} },
-	etype.Msgtext:         func() anyContent { return &MsgTextContent{} },
-	etype.Msgfile:         func() anyContent { return &MsgFileContent{} },
-	etype.Msgedit:         func() anyContent { return &MsgEditContent{} },
-	etype.Stateaccessmode: func() anyContent { return &AccessModeContent{} },
+	etype.Accessadd:        func() anyContent { return &accessAddContent{} },
+	etype.Create:           func() anyContent { return &CreationContent{} },
+	etype.Memberpromote:    func() anyContent { return &MemberRoleContent{} },
+	etype.Memberdemote:     func() anyContent { return &MemberRoleContent{} },
+	etype.Msgtext:          func() anyContent { return &MsgTextContent{} },
+	etype.Msgfile:          func() anyContent { return &MsgFileContent{} },
+	etype.Msgedit:          func() anyContent { return &MsgEditContent{} },
+	etype.Msgreaction:      func() anyContent { return &MsgReactionContent{} },
+	etype.Msgreply:         func() anyContent { return &MsgTextContent{} },
+	etype.Stateaccessmode:  func() anyContent { return &AccessModeContent{} },
+	etype.Statetitle:       func() anyContent { return &TitleContent{} },
+	etype.Statedescription: func() anyContent { return &DescriptionContent{} },
+	etype.Stateretention:   func() anyContent { return &RetentionContent{} },
+	etype.Statearchived:    func() anyContent { return &ArchivedContent{} },
 }
 
 func bindAndValidateContent(e *Event) error {
@@ -118,6 +118,15 @@ func GetCreateInfo(
 	info.Pubkey = content.PublicKey
 	info.Title = content.Title
 	info.CreatorID = e.SenderID
+
+	// the title might have been updated since the creation
+	lastTitle, err := getLastTitle(ctx, exec, boxID)
+	if err != nil {
+		return info, merr.From(err).Desc("getting last title")
+	}
+	if lastTitle.Valid {
+		info.Title = lastTitle.String
+	}
 	return info, nil
 }
 
@@ -130,6 +139,28 @@ func ListCreateByCreatorID(ctx context.Context, exec boil.ContextExecutor, creat
 	return createEvents, err
 }
 
+// ListActiveCreateByCreatorID returns the create events of boxes the creator has not left
+func ListActiveCreateByCreatorID(ctx context.Context, exec boil.ContextExecutor, creatorID string) ([]Event, error) {
+	return list(ctx, exec, eventFilters{
+		eType:    null.StringFrom(etype.Create),
+		senderID: null.StringFrom(creatorID),
+		// exclude the create events referred by a leave of the creator
+		excludeOnRef: &referentsFilters{
+			eTypes:   []string{etype.Memberleave},
+			senderID: null.StringFrom(creatorID),
+		},
+	})
+}
+
+// ListCreateByOwnerOrgID returns the create events of boxes owned by the organization
+func ListCreateByOwnerOrgID(ctx context.Context, exec boil.ContextExecutor, ownerOrgID string) ([]Event, error) {
+	jsonQuery := `{"owner_org_id": "` + ownerOrgID + `"}`
+	return list(ctx, exec, eventFilters{
+		eType:   null.StringFrom(etype.Create),
+		content: &jsonQuery,
+	})
+}
+
 // MapCreationContentByBoxID retrieves the create event of the boxes and marshal its content
 // it returns a map[boxID]CreationContent
 func MapCreationContentByBoxID(
@@ -155,25 +186,6 @@ func MapCreationContentByBoxID(
 	return contentByBoxID, nil
 }
 
-func isCreator(ctx context.Context, exec boil.ContextExecutor, boxID, senderID string) (bool, error) {
-	_, err := get(ctx, exec, eventFilters{
-		eType:    null.StringFrom(etype.Create),
-		senderID: null.StringFrom(senderID),
-		boxID:    null.StringFrom(boxID),
-	})
-	// if no error, the user is the creator
-	if err == nil {
-		return true, nil
-	}
-
-	// not found error means the user is not the creator
-	if merr.IsANotFound(err) {
-		return false, nil
-	}
-	// return the error if unexpected
-	return false, err
-}
-
 func isDataSubject(ctx context.Context, exec boil.ContextExecutor, boxID, senderID string) (bool, error) {
 	event, err := get(ctx, exec, eventFilters{
 		eType: null.StringFrom(etype.Create),
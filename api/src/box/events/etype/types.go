@@ -3,33 +3,51 @@ package etype
 // Event types constants
 const (
 	// event types
-	Accessadd       = "access.add"
-	Accessrm        = "access.rm"
-	Create          = "create"
-	Memberjoin      = "member.join"
-	Memberleave     = "member.leave"
-	Memberkick      = "member.kick"
-	Msgtext         = "msg.text"
-	Msgfile         = "msg.file"
-	Msgedit         = "msg.edit"
-	Msgdelete       = "msg.delete"
-	Stateaccessmode = "state.access_mode"
-	Statekeyshare   = "state.key_share"
+	Accessadd        = "access.add"
+	Accessrm         = "access.rm"
+	Create           = "create"
+	Memberjoin       = "member.join"
+	Memberleave      = "member.leave"
+	Memberkick       = "member.kick"
+	Memberpromote    = "member.promote"
+	Memberdemote     = "member.demote"
+	Msgtext          = "msg.text"
+	Msgfile          = "msg.file"
+	Msgedit          = "msg.edit"
+	Msgdelete        = "msg.delete"
+	Msgreaction      = "msg.reaction"
+	Msgreply         = "msg.reply"
+	Stateaccessmode  = "state.access_mode"
+	Statekeyshare    = "state.key_share"
+	Statetitle       = "state.title"
+	Statedescription = "state.description"
+	Stateretention   = "state.retention"
+	Statearchived    = "state.archived"
 
 	// events batch type
 	BatchAccesses = "accesses"
 )
 
 // MembersCanSee contains all event types that can be seen by members
-var MembersCanSee = []string{Create, Memberjoin, Memberleave, Memberkick, Msgtext, Msgfile, Statekeyshare, Stateaccessmode}
+var MembersCanSee = []string{Create, Memberjoin, Memberleave, Memberkick, Memberpromote, Memberdemote, Msgtext, Msgfile, Msgreply, Statekeyshare, Stateaccessmode, Statetitle, Statedescription, Stateretention, Statearchived}
+
+// MembersCanSync contains all event types that can be seen by members
+// including events modifying other ones, in order to synchronise a stream of events
+var MembersCanSync = []string{Create, Memberjoin, Memberleave, Memberkick, Memberpromote, Memberdemote, Msgtext, Msgfile, Msgreply, Msgedit, Msgdelete, Msgreaction, Statekeyshare, Stateaccessmode, Statetitle, Statedescription, Stateretention, Statearchived}
+
+// Messages contains all event types initiating a message, that can be modified by other events
+var Messages = []string{Msgtext, Msgfile, Msgreply}
 
 // RequireToBuild contains all event types required to build the box
-var RequireToBuild = []string{Create, Stateaccessmode, Statekeyshare}
+var RequireToBuild = []string{Create, Stateaccessmode, Statekeyshare, Statetitle, Statedescription, Stateretention, Statearchived, Memberpromote, Memberdemote, Memberleave, Memberkick}
+
+// DefineAdmins contains all event types required to compute the admins of the box
+var DefineAdmins = []string{Create, Memberpromote, Memberdemote, Memberleave, Memberkick}
 
 // RequiresContent returns all events needing a content
 func RequiresContent(eType string) bool {
 	switch eType {
-	case Accessadd, Create, Msgtext, Msgfile, Msgedit, Stateaccessmode:
+	case Accessadd, Create, Memberpromote, Memberdemote, Msgtext, Msgfile, Msgedit, Msgreaction, Msgreply, Stateaccessmode, Statetitle, Statedescription, Stateretention, Statearchived:
 		return true
 	}
 	return false
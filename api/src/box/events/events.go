@@ -87,16 +87,75 @@ func GetLast(ctx context.Context, exec boil.ContextExecutor, boxID string) (Even
 	})
 }
 
-// ListForMembersByBoxID ...
-func ListForMembersByBoxID(ctx context.Context, exec boil.ContextExecutor, boxID string, offset, limit *int) ([]Event, error) {
+// ListForMembersByBoxID lists events members can see, from the most recent one to the oldest one.
+// before and after are optional event ids used as cursors: only events strictly older than before
+// and strictly more recent than after are returned.
+func ListForMembersByBoxID(
+	ctx context.Context, exec boil.ContextExecutor,
+	boxID string, offset, limit *int, before, after *string,
+) ([]Event, error) {
+	beforeEvent, err := getCursor(ctx, exec, boxID, before, "before")
+	if err != nil {
+		return nil, err
+	}
+	afterEvent, err := getCursor(ctx, exec, boxID, after, "after")
+	if err != nil {
+		return nil, err
+	}
 	return list(ctx, exec, eventFilters{
 		boxID:  null.StringFrom(boxID),
 		offset: offset,
 		limit:  limit,
+		before: beforeEvent,
+		after:  afterEvent,
 		eTypes: etype.MembersCanSee,
 	})
 }
 
+// ListSinceForMembersByBoxID lists events that have occurred after the since event,
+// including the ones modifying other events (edition, deletion, reaction...).
+// Events are returned from the oldest one to the most recent one so they can be replayed.
+func ListSinceForMembersByBoxID(ctx context.Context, exec boil.ContextExecutor, boxID, since string, limit *int) ([]Event, error) {
+	sinceEvent, err := getCursor(ctx, exec, boxID, &since, "since")
+	if err != nil {
+		return nil, err
+	}
+	return list(ctx, exec, eventFilters{
+		boxID:     null.StringFrom(boxID),
+		limit:     limit,
+		after:     sinceEvent,
+		ascending: true,
+		eTypes:    etype.MembersCanSync,
+	})
+}
+
+// getCursor returns the event corresponding to the cursor id within the box
+// it returns nil if no cursor id is given
+func getCursor(ctx context.Context, exec boil.ContextExecutor, boxID string, cursorID *string, field string) (*Event, error) {
+	if cursorID == nil {
+		return nil, nil
+	}
+	cursor, err := get(ctx, exec, eventFilters{
+		id:    null.StringFromPtr(cursorID),
+		boxID: null.StringFrom(boxID),
+	})
+	if err != nil {
+		if merr.IsANotFound(err) {
+			return nil, merr.BadRequest().Desc("unknown cursor").Add(field, merr.DVNotFound)
+		}
+		return nil, merr.From(err).Descf("getting %s cursor", field)
+	}
+	return &cursor, nil
+}
+
+// ListAllByBoxID lists all events of a box, whatever their type, from the oldest one to the most recent one.
+func ListAllByBoxID(ctx context.Context, exec boil.ContextExecutor, boxID string) ([]Event, error) {
+	return list(ctx, exec, eventFilters{
+		boxID:     null.StringFrom(boxID),
+		ascending: true,
+	})
+}
+
 // ListLastestForEachBoxID returns the latest events of each box id.
 func ListLastestForEachBoxID(ctx context.Context, exec boil.ContextExecutor, boxIDs []string) ([]Event, error) {
 	mods := []qm.QueryMod{
@@ -190,6 +249,7 @@ type eventFilters struct {
 	// filters triggering in jsonb research
 	content          *string
 	fileID           null.String
+	versionFileID    null.String
 	restrictionType  null.String
 	restrictionTypes []string
 	accessValue      null.String
@@ -200,6 +260,11 @@ type eventFilters struct {
 	// pagination
 	offset *int
 	limit  *int
+	// cursor pagination: keep events strictly older than before and strictly more recent than after
+	before *Event
+	after  *Event
+	// sort events from the oldest one to the most recent one - default is the opposite
+	ascending bool
 }
 
 type referentsFilters struct {
@@ -270,9 +335,14 @@ func list(ctx context.Context, exec boil.ContextExecutor, filters eventFilters)
 }
 
 func buildMods(ctx context.Context, exec boil.ContextExecutor, filters eventFilters) ([]qm.QueryMod, error) {
+	order := " DESC"
+	if filters.ascending {
+		order = " ASC"
+	}
 	mods := []qm.QueryMod{
 		// NOTE: get() function count on this to always get the latest event
-		qm.OrderBy(sqlboiler.EventColumns.CreatedAt + " DESC"),
+		// the id is used as a tie-breaker to have a stable order for cursors
+		qm.OrderBy(sqlboiler.EventColumns.CreatedAt + order + ", " + sqlboiler.EventColumns.ID + order),
 	}
 	// select only the id if asked
 	if filters.idOnly {
@@ -326,6 +396,10 @@ func buildMods(ctx context.Context, exec boil.ContextExecutor, filters eventFilt
 	if filters.fileID.Valid {
 		mods = append(mods, qm.Where(`content->>'encrypted_file_id' = ?`, filters.fileID.String))
 	}
+	// add new version of encrypted file id JSONB matching
+	if filters.versionFileID.Valid {
+		mods = append(mods, qm.Where(`content->>'new_encrypted_file_id' = ?`, filters.versionFileID.String))
+	}
 
 	// add restriction type in restrictionTypes slice
 	if filters.restrictionType.Valid {
@@ -342,6 +416,21 @@ func buildMods(ctx context.Context, exec boil.ContextExecutor, filters eventFilt
 		mods = append(mods, qm.Where(`content->>'value' = ?`, filters.accessValue.String))
 	}
 
+	// add cursors for pagination
+	// Note that there is no risk of SQL injection since columns are not controlled by the user
+	if filters.before != nil {
+		mods = append(mods, qm.Where(
+			"("+sqlboiler.EventColumns.CreatedAt+", "+sqlboiler.EventColumns.ID+") < (?, ?)",
+			filters.before.CreatedAt, filters.before.ID,
+		))
+	}
+	if filters.after != nil {
+		mods = append(mods, qm.Where(
+			"("+sqlboiler.EventColumns.CreatedAt+", "+sqlboiler.EventColumns.ID+") > (?, ?)",
+			filters.after.CreatedAt, filters.after.ID,
+		))
+	}
+
 	// add offset for pagination
 	if filters.offset != nil {
 		mods = append(mods, qm.Offset(*filters.offset))
@@ -404,7 +493,7 @@ func referredEventIDs(ctx context.Context, exec boil.ContextExecutor, filters re
 	return referredIDs, nil
 }
 
-// ListFilesID ...
+// ListFilesID returns the ids of the files of the box including all their versions
 func ListFilesID(ctx context.Context, exec boil.ContextExecutor, boxID string) ([]string, error) {
 	events, err := list(ctx, exec, eventFilters{
 		boxID: null.StringFrom(boxID),
@@ -424,6 +513,23 @@ func ListFilesID(ctx context.Context, exec boil.ContextExecutor, boxID string) (
 		ids[idx] = content.EncryptedFileID
 	}
 
+	editEvents, err := list(ctx, exec, eventFilters{
+		boxID: null.StringFrom(boxID),
+		eType: null.StringFrom(etype.Msgedit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range editEvents {
+		var editContent MsgEditContent
+		if err := json.Unmarshal(event.JSONContent, &editContent); err != nil {
+			return nil, merr.Internal().Desc("unmarshaling content json")
+		}
+		if editContent.NewEncryptedFileID != "" {
+			ids = append(ids, editContent.NewEncryptedFileID)
+		}
+	}
+
 	return ids, nil
 }
 
@@ -1,10 +1,20 @@
 package events
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/repositories/sqlboiler"
 )
 
 func TestNewWithAnyContent(t *testing.T) {
@@ -23,3 +33,96 @@ func TestNewWithAnyContent(t *testing.T) {
 		assert.WithinDurationf(t, time.Now(), e.CreatedAt, time.Second, "created_at")
 	})
 }
+
+// emptyDriver is a database driver answering all queries with no rows
+type emptyDriver struct{}
+
+func (emptyDriver) Open(string) (driver.Conn, error) { return emptyConn{}, nil }
+
+type emptyConn struct{}
+
+func (emptyConn) Prepare(string) (driver.Stmt, error) { return emptyStmt{}, nil }
+func (emptyConn) Close() error                        { return nil }
+func (emptyConn) Begin() (driver.Tx, error)           { return nil, errors.New("transactions not supported") }
+
+type emptyStmt struct{}
+
+func (emptyStmt) Close() error                               { return nil }
+func (emptyStmt) NumInput() int                              { return -1 }
+func (emptyStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (emptyStmt) Query([]driver.Value) (driver.Rows, error)  { return emptyRows{}, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return []string{"id"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("empty", emptyDriver{})
+}
+
+func TestBuildModsCursors(t *testing.T) {
+	createdAt := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	limit := 10
+	tests := map[string]struct {
+		filters       eventFilters
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		"before a cursor sharing its created_at with other events": {
+			filters:       eventFilters{before: &Event{ID: "event-B", CreatedAt: createdAt}},
+			expectedQuery: `SELECT * FROM "event" WHERE ((created_at, id) < ($1, $2)) ORDER BY created_at DESC, id DESC;`,
+			expectedArgs:  []interface{}{createdAt, "event-B"},
+		},
+		"after a cursor sharing its created_at with other events": {
+			filters:       eventFilters{after: &Event{ID: "event-B", CreatedAt: createdAt}},
+			expectedQuery: `SELECT * FROM "event" WHERE ((created_at, id) > ($1, $2)) ORDER BY created_at DESC, id DESC;`,
+			expectedArgs:  []interface{}{createdAt, "event-B"},
+		},
+		"cursor and limit": {
+			filters: eventFilters{
+				after:     &Event{ID: "event-B", CreatedAt: createdAt},
+				limit:     &limit,
+				ascending: true,
+			},
+			expectedQuery: `SELECT * FROM "event" WHERE ((created_at, id) > ($1, $2)) ORDER BY created_at ASC, id ASC LIMIT 10;`,
+			expectedArgs:  []interface{}{createdAt, "event-B"},
+		},
+		"both cursors": {
+			filters: eventFilters{
+				before: &Event{ID: "event-C", CreatedAt: createdAt.Add(time.Second)},
+				after:  &Event{ID: "event-A", CreatedAt: createdAt},
+			},
+			expectedQuery: `SELECT * FROM "event" WHERE ((created_at, id) < ($1, $2)) AND ((created_at, id) > ($3, $4)) ORDER BY created_at DESC, id DESC;`,
+			expectedArgs:  []interface{}{createdAt.Add(time.Second), "event-C", createdAt, "event-A"},
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			mods, err := buildMods(context.Background(), nil, test.filters)
+			assert.Nil(t, err)
+			query, args := queries.BuildQuery(sqlboiler.Events(mods...).Query)
+			assert.Equal(t, test.expectedQuery, query)
+			assert.Equal(t, test.expectedArgs, args)
+		})
+	}
+
+	t.Run("unknown cursor id", func(t *testing.T) {
+		db, err := sql.Open("empty", "")
+		assert.Nil(t, err)
+		defer db.Close()
+
+		cursorID := "unknown-event"
+		_, err = getCursor(context.Background(), db, "box-A", &cursorID, "before")
+		assert.Error(t, err)
+		assert.Equal(t, merr.BadRequestCode, merr.From(err).Co)
+		assert.Equal(t, merr.DVNotFound, merr.From(err).Details["before"])
+	})
+
+	t.Run("no cursor id", func(t *testing.T) {
+		cursor, err := getCursor(context.Background(), nil, "box-A", nil, "before")
+		assert.Nil(t, err)
+		assert.Nil(t, cursor)
+	})
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/etype"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/repositories/sqlboiler"
+)
+
+//
+// this file contains logic about messages expiration
+// a message expires:
+// - after its own time-to-live, set in its content on creation
+// - after the retention set on its box by an admin using a state.retention event.
+
+// MaxExpirationSeconds bounds time-to-lives and retentions to a hundred years,
+// larger durations would not fit in a time.Duration.
+const MaxExpirationSeconds = int64(100 * 365 * 24 * 60 * 60)
+
+// ListExpiredMessageIDs returns ids of not-yet-deleted messages that have expired at the given time.
+func ListExpiredMessageIDs(ctx context.Context, exec boil.ContextExecutor, now time.Time) ([]string, error) {
+	// build a set to ensure unicity since a message can expire for both reasons
+	uniqIDs := make(map[string]bool)
+
+	// 1. messages whose time-to-live is over
+	ttlIDs, err := listLiveMessageIDs(ctx, exec,
+		qm.Where(`content->>'ttl' IS NOT NULL`),
+		// the ttl is clamped so events persisted before it was bounded cannot overflow the interval
+		qm.Where(sqlboiler.EventColumns.CreatedAt+` + make_interval(secs => LEAST((content->>'ttl')::numeric, ?)::double precision) < ?`, MaxExpirationSeconds, now),
+	)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing messages with ttl")
+	}
+	for _, id := range ttlIDs {
+		uniqIDs[id] = true
+	}
+
+	// 2. messages older than the retention of their box
+	retentions, err := listLastestRetentionEvents(ctx, exec)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing retentions")
+	}
+	for _, retention := range retentions {
+		var content RetentionContent
+		if err := content.Unmarshal(retention.JSONContent); err != nil {
+			return nil, merr.From(err).Desc("unmarshalling retention content")
+		}
+		// the retention has been removed on this box
+		if content.Value == 0 {
+			continue
+		}
+		retentionIDs, err := listLiveMessageIDs(ctx, exec,
+			sqlboiler.EventWhere.BoxID.EQ(retention.BoxID),
+			sqlboiler.EventWhere.CreatedAt.LT(retentionCutoff(now, content.Value)),
+		)
+		if err != nil {
+			return nil, merr.From(err).Descf("listing messages of box %s", retention.BoxID)
+		}
+		for _, id := range retentionIDs {
+			uniqIDs[id] = true
+		}
+	}
+
+	ids := make([]string, 0, len(uniqIDs))
+	for id := range uniqIDs {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// retentionCutoff returns the time before which messages are older than the retention.
+// The retention is clamped so events persisted before it was bounded cannot overflow.
+func retentionCutoff(now time.Time, retention int64) time.Time {
+	if retention > MaxExpirationSeconds {
+		retention = MaxExpirationSeconds
+	}
+	return now.Add(-time.Duration(retention) * time.Second)
+}
+
+// ExpireMessage deletes the message with the given id on behalf of the system.
+// The msg.delete event is considered as sent by the initial sender of the message
+// and the potential encrypted file is removed if not used anymore.
+// It returns the created event and the message as metadata for after handlers.
+func ExpireMessage(ctx context.Context, exec boil.ContextExecutor, filesRepo files.FileStorageRepo, messageID string) (Event, *Message, error) {
+	msg, err := buildMessage(ctx, exec, messageID)
+	if err != nil {
+		return Event{}, nil, merr.From(err).Desc("building message")
+	}
+	// if the message is already deleted, do not go further
+	if !msg.DeletedAt.IsZero() {
+		return Event{}, nil, merr.Gone().Desc("cannot expire an already deleted message")
+	}
+
+	e, err := New(etype.Msgdelete, nil, msg.BoxID, msg.InitialSenderID, &messageID)
+	if err != nil {
+		return e, nil, merr.From(err).Desc("newing delete event")
+	}
+	if err := e.persist(ctx, exec); err != nil {
+		return e, nil, err
+	}
+	// add the recently created event to the built message
+	if err := msg.addEvent(e); err != nil {
+		return e, nil, err
+	}
+
+	// (potential) removal of the actual encrypted file is done at the very end
+	// because the operation cannot be rolled back
+	if err := DeleteOrphanFiles(ctx, exec, filesRepo, msg.FileVersionIDs); err != nil {
+		return e, nil, merr.From(err).Desc("deleting orphan files")
+	}
+	return e, &msg, nil
+}
+
+// listLiveMessageIDs returns the ids of messages not deleted yet, considering additional query mods.
+func listLiveMessageIDs(ctx context.Context, exec boil.ContextExecutor, mods ...qm.QueryMod) ([]string, error) {
+	mods = append(mods,
+		qm.Select(sqlboiler.EventColumns.ID),
+		sqlboiler.EventWhere.Type.IN(etype.Messages),
+		// Note that there is no risk of SQL injection since no value is controlled by the user
+		qm.Where(`NOT EXISTS (SELECT 1 FROM event AS ref WHERE ref.referrer_id = event.id AND ref.type = ?)`, etype.Msgdelete),
+	)
+	records, err := sqlboiler.Events(mods...).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids, nil
+}
+
+// listLastestRetentionEvents returns the latest state.retention event of each box.
+func listLastestRetentionEvents(ctx context.Context, exec boil.ContextExecutor) ([]Event, error) {
+	mods := []qm.QueryMod{
+		qm.Select("DISTINCT ON (box_id) box_id, event.*"),
+		sqlboiler.EventWhere.Type.EQ(etype.Stateretention),
+		qm.OrderBy(sqlboiler.EventColumns.BoxID),
+		qm.OrderBy(sqlboiler.EventColumns.CreatedAt + " DESC"),
+	}
+	records, err := sqlboiler.Events(mods...).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	retentions := make([]Event, len(records))
+	for i, record := range records {
+		retentions[i] = fromSQLBoiler(record)
+	}
+	return retentions, nil
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		retention int64
+		expected  time.Time
+	}{
+		"one day": {
+			retention: 24 * 60 * 60,
+			expected:  now.Add(-24 * time.Hour),
+		},
+		"maximum retention": {
+			retention: MaxExpirationSeconds,
+			expected:  now.Add(-time.Duration(MaxExpirationSeconds) * time.Second),
+		},
+		"huge retention is clamped instead of overflowing": {
+			retention: math.MaxInt64,
+			expected:  now.Add(-time.Duration(MaxExpirationSeconds) * time.Second),
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			cutoff := retentionCutoff(now, test.retention)
+			assert.Equal(t, test.expected, cutoff)
+			// messages sent recently are never older than the retention
+			assert.True(t, cutoff.Before(now.Add(-time.Hour)))
+		})
+	}
+}
+
+func TestRetentionContentValidate(t *testing.T) {
+	assert.NoError(t, RetentionContent{Value: 0}.Validate())
+	assert.NoError(t, RetentionContent{Value: MaxExpirationSeconds}.Validate())
+	assert.Error(t, RetentionContent{Value: -1}.Validate())
+	assert.Error(t, RetentionContent{Value: MaxExpirationSeconds + 1}.Validate())
+	assert.Error(t, RetentionContent{Value: math.MaxInt64}.Validate())
+}
+
+func TestMsgTextContentValidateTTL(t *testing.T) {
+	ttl := func(value int64) *int64 { return &value }
+	content := MsgTextContent{Encrypted: "ZW5jcnlwdGVk", PublicKey: "public_key"}
+	assert.NoError(t, content.Validate())
+
+	content.TTL = ttl(MaxExpirationSeconds)
+	assert.NoError(t, content.Validate())
+	content.TTL = ttl(math.MaxInt64)
+	assert.Error(t, content.Validate())
+}
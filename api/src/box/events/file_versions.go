@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/etype"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// FileVersion is one of the encrypted files successively attached to a msg.file.
+// The first version comes from the msg.file itself, the following ones from msg.edit events.
+type FileVersion struct {
+	Number          int       `json:"number"`
+	EventID         string    `json:"event_id"`
+	EncryptedFileID string    `json:"encrypted_file_id"`
+	Size            int64     `json:"size"`
+	Encrypted       string    `json:"encrypted"`
+	PublicKey       string    `json:"public_key"`
+	SenderID        string    `json:"sender_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ListFileVersions of the msg.file with the given id, from the oldest one
+func ListFileVersions(ctx context.Context, exec boil.ContextExecutor, boxID, eventID string) ([]FileVersion, error) {
+	msgEvents, err := listEventAndReferrers(ctx, exec, eventID)
+	if err != nil {
+		return nil, err
+	}
+	initialEvent := msgEvents[0]
+	if initialEvent.Type != etype.Msgfile || initialEvent.BoxID != boxID {
+		return nil, merr.NotFound().Add("id", merr.DVNotFound)
+	}
+
+	var content MsgFileContent
+	if err := content.Unmarshal(initialEvent.JSONContent); err != nil {
+		return nil, merr.From(err).Desc("unmarshalling file content")
+	}
+	file, err := files.Get(ctx, exec, content.EncryptedFileID)
+	if err != nil {
+		return nil, merr.From(err).Desc("getting initial file")
+	}
+	versions := []FileVersion{{
+		Number:          1,
+		EventID:         initialEvent.ID,
+		EncryptedFileID: content.EncryptedFileID,
+		Size:            file.Size,
+		Encrypted:       content.Encrypted,
+		PublicKey:       content.PublicKey,
+		SenderID:        initialEvent.SenderID,
+		CreatedAt:       initialEvent.CreatedAt,
+	}}
+
+	for _, e := range msgEvents[1:] {
+		switch e.Type {
+		case etype.Msgdelete:
+			// the versions are removed with the message
+			return nil, merr.Gone().Desc("the message has been deleted")
+		case etype.Msgedit:
+			var editContent MsgEditContent
+			if err := editContent.Unmarshal(e.JSONContent); err != nil {
+				return nil, merr.From(err).Desc("unmarshalling edit content")
+			}
+			if editContent.NewEncryptedFileID == "" {
+				continue
+			}
+			versions = append(versions, FileVersion{
+				Number:          len(versions) + 1,
+				EventID:         e.ID,
+				EncryptedFileID: editContent.NewEncryptedFileID,
+				Size:            editContent.NewFileSize,
+				Encrypted:       editContent.NewEncrypted,
+				PublicKey:       editContent.NewPublicKey,
+				SenderID:        e.SenderID,
+				CreatedAt:       e.CreatedAt,
+			})
+		}
+	}
+	return versions, nil
+}
+
+// ListFileVersionIDs returns the ids of the files added as new versions of the given msg.file events
+func ListFileVersionIDs(ctx context.Context, exec boil.ContextExecutor, eventIDs []string) ([]string, error) {
+	if len(eventIDs) == 0 {
+		return []string{}, nil
+	}
+	editEvents, err := list(ctx, exec, eventFilters{
+		eType:       null.StringFrom(etype.Msgedit),
+		referrerIDs: eventIDs,
+		ascending:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	fileIDs := []string{}
+	for _, e := range editEvents {
+		var content MsgEditContent
+		if err := content.Unmarshal(e.JSONContent); err != nil {
+			return nil, merr.From(err).Desc("unmarshalling edit content")
+		}
+		if content.NewEncryptedFileID != "" {
+			fileIDs = append(fileIDs, content.NewEncryptedFileID)
+		}
+	}
+	return fileIDs, nil
+}
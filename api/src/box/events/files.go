@@ -56,7 +56,8 @@ func SetSavedStatus(ctx context.Context, exec boil.ContextExecutor, identityID s
 	return nil
 }
 
-// IsFileOrphan ...
+// IsFileOrphan tells if no saved file nor msg.file event refers the encrypted file anymore.
+// The stored data of an orphan file might still be shared with other encrypted files.
 func IsFileOrphan(ctx context.Context, exec boil.ContextExecutor, fileID string) (bool, error) {
 	// check that there is no saved file referring this file
 	filters := files.SavedFileFilters{
@@ -80,15 +81,30 @@ func IsFileOrphan(ctx context.Context, exec boil.ContextExecutor, fileID string)
 	if err != nil {
 		return false, err
 	}
+	// the file can also be a version of the file of msg.file messages
+	versionEvents, err := list(ctx, exec, eventFilters{
+		eType:         null.StringFrom(etype.Msgedit),
+		versionFileID: null.StringFrom(fileID),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// build list of messages ids to see if all of them have been deleted
+	messageIDs := make(map[string]bool)
+	for _, e := range filePartialEvents {
+		messageIDs[e.ID] = true
+	}
+	for _, e := range versionEvents {
+		messageIDs[e.ReferrerID.String] = true
+	}
 	// if no event found, the file is orphan - should not happen
-	if len(filePartialEvents) == 0 {
+	if len(messageIDs) == 0 {
 		return true, nil
 	}
-
-	// build list of ids to see if all of them have been deleted
-	ids := make([]string, len(filePartialEvents))
-	for i, e := range filePartialEvents {
-		ids[i] = e.ID
+	ids := make([]string, 0, len(messageIDs))
+	for id := range messageIDs {
+		ids = append(ids, id)
 	}
 	deletePartialEvents, err := list(ctx, exec, eventFilters{
 		idOnly:      true,
@@ -98,8 +114,8 @@ func IsFileOrphan(ctx context.Context, exec boil.ContextExecutor, fileID string)
 	if err != nil {
 		return false, err
 	}
-	// if at least one file events is not referred by a delete event, the file is not orphan
-	if len(deletePartialEvents) != len(filePartialEvents) {
+	// if at least one message is not referred by a delete event, the file is not orphan
+	if len(deletePartialEvents) != len(ids) {
 		return false, nil
 	}
 
@@ -154,6 +170,17 @@ func HasAccessToFile(
 	if err != nil {
 		return false, err
 	}
+	// the file can also be a version of the file of a msg.file
+	versionPartialEvents, err := list(ctx, exec, eventFilters{
+		boxIDOnly:     true,
+		eType:         null.StringFrom(etype.Msgedit),
+		versionFileID: null.StringFrom(fileID),
+	})
+	if err != nil {
+		return false, err
+	}
+	filePartialEvents = append(filePartialEvents, versionPartialEvents...)
+
 	// for each file event, check the user has currently access to the box
 	for _, event := range filePartialEvents {
 		err := MustBeMember(ctx, exec, redConn, event.BoxID, identityID)
@@ -170,7 +197,9 @@ func HasAccessToFile(
 	return false, nil
 }
 
-// DeleteOrphanFiles ...
+// DeleteOrphanFiles removes the orphan encrypted files.
+// Each removal decrements the references to the stored data,
+// which is deleted only once no encrypted file refers it anymore.
 func DeleteOrphanFiles(ctx context.Context, exec boil.ContextExecutor, filesRepo files.FileStorageRepo, fileIDs []string) error {
 	for _, fileID := range fileIDs {
 		// we need to check the existency of fileID
@@ -71,13 +71,23 @@ var eventTypeHandlerMapping = map[string]EventHandler{
 	etype.Memberleave: {doLeave, group(sendRealtimeUpdate, countActivity, invalidateCaches)},
 	etype.Memberjoin:  {doJoin, group(sendRealtimeUpdate, countActivity, invalidateCaches)},
 
+	etype.Memberpromote: {doPromote, group(sendRealtimeUpdate, countActivity, invalidateCaches)},
+	etype.Memberdemote:  {doDemote, group(sendRealtimeUpdate, countActivity, invalidateCaches)},
+
 	etype.Msgdelete: {doDeleteMsg, group(sendRealtimeUpdate, computeUsedSpace)},
 	etype.Msgedit:   {doEditMsg, group(sendRealtimeUpdate, computeUsedSpace)},
 	etype.Msgfile:   {doMessage, group(sendRealtimeUpdate, countActivity, computeUsedSpace)},
 	etype.Msgtext:   {doMessage, group(sendRealtimeUpdate, countActivity, computeUsedSpace)},
 
-	etype.Stateaccessmode: {doStateAccessMode, group(sendRealtimeUpdate, countActivity)},
-	etype.Statekeyshare:   {doStateKeyShare, nil},
+	etype.Msgreaction: {doReaction, group(sendRealtimeUpdate)},
+	etype.Msgreply:    {doReply, group(sendRealtimeUpdate, countActivity, computeUsedSpace)},
+
+	etype.Stateaccessmode:  {doStateAccessMode, group(sendRealtimeUpdate, countActivity)},
+	etype.Statekeyshare:    {doStateKeyShare, nil},
+	etype.Statetitle:       {doStateTitle, group(sendRealtimeUpdate, countActivity)},
+	etype.Statedescription: {doStateDescription, group(sendRealtimeUpdate, countActivity)},
+	etype.Stateretention:   {doStateRetention, group(sendRealtimeUpdate, countActivity)},
+	etype.Statearchived:    {doStateArchived, group(sendRealtimeUpdate, countActivity)},
 
 	// never added by end-users directly but the system
 	etype.Memberkick: {empty, group(notifyKick, sendRealtimeUpdate, countActivity, invalidateCaches)},
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+)
+
+// ImportedMetadata describes the original event an imported event has been created from.
+// It is kept in the content of imported messages since the event itself is sent by the importer.
+type ImportedMetadata struct {
+	EventID   string     `json:"event_id"`
+	CreatedAt time.Time  `json:"server_event_created_at"`
+	Sender    SenderView `json:"sender"`
+}
+
+// importableContent is a content able to hold imported metadata
+type importableContent interface {
+	anyContent
+	getImported() *ImportedMetadata
+	setImported(*ImportedMetadata)
+}
+
+// ImportEvent creates and persists in the box a new event based on the view of an exported event.
+// The new event is sent by the importer: the original sender and creation time are kept
+// in the content as imported metadata if the content allows it.
+// The content of an event already imported keeps its initial imported metadata.
+func ImportEvent(
+	ctx context.Context, exec boil.ContextExecutor,
+	original View, content types.JSON,
+	boxID, senderID string, referrerID *string,
+) (Event, error) {
+	contentTypeGet, ok := contentTypeGetters[original.Type]
+	if !ok {
+		e, err := New(original.Type, content, boxID, senderID, referrerID)
+		if err != nil {
+			return e, err
+		}
+		return e, e.persist(ctx, exec)
+	}
+
+	c := contentTypeGet()
+	if err := content.Unmarshal(c); err != nil {
+		return Event{}, merr.BadRequest().Descf("unmarshalling %s: %v", original.Type, err)
+	}
+	importable, ok := c.(importableContent)
+	if !ok {
+		e, err := newWithAnyContent(original.Type, c, boxID, senderID, referrerID)
+		if err != nil {
+			return e, err
+		}
+		return e, e.persist(ctx, exec)
+	}
+
+	imported := importable.getImported()
+	if imported == nil {
+		imported = &ImportedMetadata{
+			EventID:   original.ID,
+			CreatedAt: original.CreatedAt,
+			Sender:    original.Sender,
+		}
+	}
+	// end-users cannot set imported metadata so the content is validated without it
+	importable.setImported(nil)
+	e, err := newWithAnyContent(original.Type, importable, boxID, senderID, referrerID)
+	if err != nil {
+		return e, err
+	}
+	importable.setImported(imported)
+	if err := e.JSONContent.Marshal(importable); err != nil {
+		return e, merr.From(err).Descf("marshalling %s content", e.Type)
+	}
+	return e, e.persist(ctx, exec)
+}
+
+func (c *MsgTextContent) getImported() *ImportedMetadata  { return c.Imported }
+func (c *MsgTextContent) setImported(m *ImportedMetadata) { c.Imported = m }
+
+func (c *MsgFileContent) getImported() *ImportedMetadata  { return c.Imported }
+func (c *MsgFileContent) setImported(m *ImportedMetadata) { c.Imported = m }
+
+func (c *MsgEditContent) getImported() *ImportedMetadata  { return c.Imported }
+func (c *MsgEditContent) setImported(m *ImportedMetadata) { c.Imported = m }
@@ -68,7 +68,7 @@ func (e Event) Format(ctx context.Context, identityMapper *IdentityMapper, trans
 
 	// For deleted messages
 	// we put the deletor identifier in the content
-	if e.Type == etype.Msgtext || e.Type == etype.Msgfile {
+	if e.Type == etype.Msgtext || e.Type == etype.Msgfile || e.Type == etype.Msgreply {
 		var content DeletedContent
 		err := json.Unmarshal(e.JSONContent, &content)
 		if err != nil {
@@ -86,6 +86,11 @@ func (e Event) Format(ctx context.Context, identityMapper *IdentityMapper, trans
 				return view, merr.From(err).Descf("marshalling %s content", e.Type)
 			}
 		}
+
+		// map the identities who have reacted to the message
+		if err := formatReactions(ctx, identityMapper, view.Content, transparent); err != nil {
+			return view, merr.From(err).Desc("formatting reactions")
+		}
 	}
 
 	if e.Type == etype.Memberkick {
@@ -109,8 +114,63 @@ func (e Event) Format(ctx context.Context, identityMapper *IdentityMapper, trans
 		}
 	}
 
+	if e.Type == etype.Memberpromote || e.Type == etype.Memberdemote {
+		var content MemberRoleContent
+		err := json.Unmarshal(e.JSONContent, &content)
+		if err != nil {
+			return view, merr.From(err).Descf("unmarshaling %s json", e.Type)
+		}
+
+		if content.IdentityID != "" {
+			identity, err := identityMapper.Get(ctx, content.IdentityID, transparent)
+			if err != nil {
+				return view, merr.From(err).Desc("getting member")
+			}
+			content.Identity = &identity
+			content.IdentityID = ""
+		}
+
+		if err := view.Content.Marshal(content); err != nil {
+			return view, merr.From(err).Desc("marshalling event content")
+		}
+	}
+
 	if view.Content.String() == "{}" {
 		view.Content = nil
 	}
 	return view, nil
 }
+
+// formatReactions aggregated in a message content by replacing the identity ids with their views
+func formatReactions(ctx context.Context, identityMapper *IdentityMapper, content *types.JSON, transparent bool) error {
+	var aggregate struct {
+		Reactions []Reaction `json:"reactions"`
+	}
+	if err := content.Unmarshal(&aggregate); err != nil {
+		return merr.From(err).Desc("unmarshaling reactions")
+	}
+	if len(aggregate.Reactions) == 0 {
+		return nil
+	}
+
+	for i, reaction := range aggregate.Reactions {
+		reaction.Identities = make([]SenderView, len(reaction.IdentityIDs))
+		for j, identityID := range reaction.IdentityIDs {
+			identity, err := identityMapper.Get(ctx, identityID, transparent)
+			if err != nil {
+				return merr.From(err).Desc("getting reaction author")
+			}
+			reaction.Identities[j] = identity
+		}
+		reaction.IdentityIDs = nil
+		aggregate.Reactions[i] = reaction
+	}
+
+	// the content is handled as a generic map since the shape depends on the message type
+	var generic map[string]interface{}
+	if err := content.Unmarshal(&generic); err != nil {
+		return merr.From(err).Desc("unmarshaling content")
+	}
+	generic["reactions"] = aggregate.Reactions
+	return content.Marshal(generic)
+}
@@ -79,4 +79,38 @@ func TestFormatEvent(t *testing.T) {
 		err = e.JSONContent.Marshal(&msg)
 		assert.Nilf(t, err, "marshal member kick content")
 	})
+
+	t.Run("the reaction authors are correctly mapped", func(t *testing.T) {
+		// prepare the event
+		msg := map[string]interface{}{
+			"encrypted":  "coucou",
+			"public_key": "pub-key",
+			"reactions":  []Reaction{{Emoji: "👍", Count: 1, IdentityIDs: []string{"reactor-A"}}},
+		}
+		var json types.JSON
+		err := json.Marshal(msg)
+		assert.Nil(t, err)
+		e := Event{
+			Type:        "msg.text",
+			JSONContent: json,
+			SenderID:    "reactor-A",
+		}
+		// we need to set into mem the identity a because the querier is not mocked
+		identities := NewIdentityMapper(nil)
+		identities.mem["reactor-A"] = SenderView{ID: "reactor-A", DisplayName: "Jin", IdentifierValue: "jin@misakey.com"}
+
+		view, err := e.Format(context.Background(), identities, false)
+		assert.Nilf(t, err, "to view")
+
+		var content struct {
+			Encrypted string     `json:"encrypted"`
+			Reactions []Reaction `json:"reactions"`
+		}
+		err = view.Content.Unmarshal(&content)
+		assert.Nilf(t, err, "unmarshal content")
+		assert.Equal(t, "coucou", content.Encrypted)
+		assert.Len(t, content.Reactions, 1)
+		assert.Nil(t, content.Reactions[0].IdentityIDs)
+		assert.Equal(t, []SenderView{{ID: "reactor-A", DisplayName: "Jin"}}, content.Reactions[0].Identities)
+	})
 }
@@ -24,6 +24,11 @@ func doJoin(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecuto
 		return nil, merr.Conflict().Desc("already box member")
 	}
 
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
 	// check the sender can join the box
 	if err := MustBeAbleToJoin(ctx, exec, identities, e.BoxID, e.SenderID); err != nil {
 		return nil, merr.From(err).Desc("checking joinability")
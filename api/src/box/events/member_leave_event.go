@@ -21,13 +21,17 @@ func doLeave(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecut
 		return nil, err
 	}
 
-	// check that the current sender is not the admin
-	// admin can’t leave their own box
-	if err := MustBeAdmin(ctx, exec, e.BoxID, e.SenderID); err == nil {
-		return nil, merr.Forbidden().Desc("admin can’t leave their own box")
+	// a box must always be managed by someone: the last admin can’t leave it
+	admins, err := ListAdminIDs(ctx, exec, e.BoxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing admins")
+	}
+	if adminIDs(admins).has(e.SenderID) && len(admins) == 1 {
+		return nil, merr.Forbidden().Desc("the last admin can’t leave the box")
 	}
 
 	// get the last join event to set the referrer id
+	// the creator has not necessarily joined the box: their create event is referred then
 	joinEvent, err := get(ctx, exec, eventFilters{
 		eType:    null.StringFrom(etype.Memberjoin),
 		senderID: null.StringFrom(e.SenderID),
@@ -39,6 +43,13 @@ func doLeave(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecut
 			boxID:    null.StringFrom(e.BoxID),
 		},
 	})
+	if merr.IsANotFound(err) {
+		joinEvent, err = get(ctx, exec, eventFilters{
+			eType:    null.StringFrom(etype.Create),
+			senderID: null.StringFrom(e.SenderID),
+			boxID:    null.StringFrom(e.BoxID),
+		})
+	}
 	if err != nil {
 		return nil, merr.From(err).Desc("getting last join event")
 	}
@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// MemberRoleContent is the content of member.promote and member.demote events
+type MemberRoleContent struct {
+	// Stored but not return in json view
+	IdentityID string `json:"identity_id,omitempty"`
+	// in json
+	Identity *SenderView `json:"identity,omitempty"`
+}
+
+// Unmarshal ...
+func (c *MemberRoleContent) Unmarshal(content types.JSON) error {
+	return content.Unmarshal(c)
+}
+
+// Validate ...
+func (c MemberRoleContent) Validate() error {
+	return v.ValidateStruct(&c,
+		v.Field(&c.IdentityID, v.Required, is.UUIDv4),
+		v.Field(&c.Identity, v.Nil),
+	)
+}
+
+func doPromote(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, redConn *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// only admins can promote other members
+	admins, err := ListAdminIDs(ctx, exec, e.BoxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing admins")
+	}
+	if !adminIDs(admins).has(e.SenderID) {
+		return nil, merr.Forbidden().Desc("not an admin")
+	}
+
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
+	var c MemberRoleContent
+	if err := c.Unmarshal(e.JSONContent); err != nil {
+		return nil, merr.From(err).Desc("unmarshalling member role content")
+	}
+
+	// the promoted identity must be a member of the box
+	if err := MustBeMember(ctx, exec, redConn, e.BoxID, c.IdentityID); err != nil {
+		return nil, merr.From(err).Desc("checking promoted membership")
+	}
+	if adminIDs(admins).has(c.IdentityID) {
+		return nil, merr.Conflict().Desc("already an admin").Add("identity_id", merr.DVConflict)
+	}
+
+	return nil, e.persist(ctx, exec)
+}
+
+func doDemote(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, _ *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// only admins can demote other admins
+	admins, err := ListAdminIDs(ctx, exec, e.BoxID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing admins")
+	}
+	if !adminIDs(admins).has(e.SenderID) {
+		return nil, merr.Forbidden().Desc("not an admin")
+	}
+
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
+	var c MemberRoleContent
+	if err := c.Unmarshal(e.JSONContent); err != nil {
+		return nil, merr.From(err).Desc("unmarshalling member role content")
+	}
+
+	if !adminIDs(admins).has(c.IdentityID) {
+		return nil, merr.Conflict().Desc("not an admin").Add("identity_id", merr.DVConflict)
+	}
+	// a box must always be managed by someone
+	if len(admins) == 1 {
+		return nil, merr.Forbidden().Desc("cannot demote the last admin").Add("identity_id", merr.DVForbidden)
+	}
+
+	return nil, e.persist(ctx, exec)
+}
@@ -28,19 +28,27 @@ func ListBoxMemberIDs(
 	}
 
 	// 2. if cache couldn’t be retrieved
-	// get the creator id which is a member
+	// get the creator id which is a member until they leave the box
 	logger.FromCtx(ctx).Debug().Msgf("regenerating members cache for %s", boxID)
+	uniqueMemberIDs := make(map[string]bool)
+	memberIDs = []string{}
 	createEvent, err := get(ctx, exec, eventFilters{
 		eType: null.StringFrom(etype.Create),
 		boxID: null.StringFrom(boxID),
+		// exclude the create event referred by a leave of the creator
+		excludeOnRef: &referentsFilters{
+			eTypes: []string{etype.Memberleave},
+			boxID:  null.StringFrom(boxID),
+		},
 	})
-	if err != nil {
+	if err != nil && !merr.IsANotFound(err) {
 		return nil, merr.From(err).Desc("getting create event")
 	}
 	// start the member IDs list with it
-	uniqueMemberIDs := make(map[string]bool)
-	memberIDs = []string{createEvent.SenderID}
-	uniqueMemberIDs[createEvent.SenderID] = true
+	if err == nil {
+		memberIDs = append(memberIDs, createEvent.SenderID)
+		uniqueMemberIDs[createEvent.SenderID] = true
+	}
 
 	// 3. compute people that has joined the box
 	activeJoins, err := listBoxActiveJoinEvents(ctx, exec, boxID)
@@ -102,14 +110,28 @@ func MustBeMember(
 		return nil
 	}
 
-	// if the creator, it is a member of the box
-	isCreator, err := isCreator(ctx, exec, boxID, identityID)
-	if err != nil {
-		return err
+	if !merr.IsANotFound(err) {
+		return merr.From(err).Desc("getting join event")
 	}
-	if isCreator {
+
+	// the creator is a member of the box until they leave it
+	_, err = get(ctx, exec, eventFilters{
+		eType:    null.StringFrom(etype.Create),
+		boxID:    null.StringFrom(boxID),
+		senderID: null.StringFrom(identityID),
+		// exclude the create event referred by a leave
+		excludeOnRef: &referentsFilters{
+			eTypes:   []string{etype.Memberleave},
+			senderID: null.StringFrom(identityID),
+			boxID:    null.StringFrom(boxID),
+		},
+	})
+	if err == nil {
 		return nil
 	}
+	if !merr.IsANotFound(err) {
+		return merr.From(err).Desc("getting create event")
+	}
 
 	return merr.Forbidden().Desc("must be a member").Add("reason", "not_member").Add("sender_id", merr.DVForbidden)
 }
@@ -14,17 +14,33 @@ import (
 
 // Message ...
 type Message struct {
-	Encrypted       string
-	PublicKey       string
-	LastEditedAt    time.Time
-	DeletedAt       time.Time
-	FileID          null.String
+	Encrypted    string
+	PublicKey    string
+	LastEditedAt time.Time
+	DeletedAt    time.Time
+	FileID       null.String
+	// FileVersionIDs contains all the versions of the file, from the oldest one
+	FileVersionIDs  []string
 	BoxID           string
 	Type            string
 	InitialSenderID string
 	LastSenderID    string
 	OldSize         int
 	NewSize         int
+
+	// interactions of members with the message
+	Reactions []Reaction
+	ReplyIDs  []string
+}
+
+// Reaction is an emoji put on a message by some members
+type Reaction struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+	// Stored but not return in json view
+	IdentityIDs []string `json:"identity_ids,omitempty"`
+	// in json
+	Identities []SenderView `json:"identities,omitempty"`
 }
 
 func buildMessage(ctx context.Context, exec boil.ContextExecutor, eventID string) (msg Message, err error) {
@@ -40,7 +56,7 @@ func buildMessage(ctx context.Context, exec boil.ContextExecutor, eventID string
 	msg.BoxID = initialEvent.BoxID
 	msg.InitialSenderID = initialEvent.SenderID
 	switch initialEvent.Type {
-	case etype.Msgtext:
+	case etype.Msgtext, etype.Msgreply:
 		var content MsgTextContent
 		if err := initialEvent.JSONContent.Unmarshal(&content); err != nil {
 			return msg, err
@@ -62,6 +78,7 @@ func buildMessage(ctx context.Context, exec boil.ContextExecutor, eventID string
 			if err == nil {
 				msg.NewSize = int(file.Size)
 				msg.FileID = null.StringFrom(content.EncryptedFileID)
+				msg.FileVersionIDs = []string{content.EncryptedFileID}
 			}
 		}
 		msg.PublicKey = content.PublicKey
@@ -84,6 +101,20 @@ func buildMessage(ctx context.Context, exec boil.ContextExecutor, eventID string
 }
 
 func (msg *Message) addEvent(e Event) error {
+	// interactions do not modify the message itself
+	switch e.Type {
+	case etype.Msgreaction:
+		var content MsgReactionContent
+		if err := e.JSONContent.Unmarshal(&content); err != nil {
+			return err
+		}
+		msg.toggleReaction(content.Emoji, e.SenderID)
+		return nil
+	case etype.Msgreply:
+		msg.ReplyIDs = append(msg.ReplyIDs, e.ID)
+		return nil
+	}
+
 	msg.LastSenderID = e.SenderID
 	switch e.Type {
 	case etype.Msgdelete:
@@ -100,9 +131,45 @@ func (msg *Message) addEvent(e Event) error {
 		msg.PublicKey = content.NewPublicKey
 		msg.LastEditedAt = e.CreatedAt
 		msg.OldSize = msg.NewSize
+		// all the versions of a file count in the used space
+		if content.NewEncryptedFileID != "" {
+			msg.FileID = null.StringFrom(content.NewEncryptedFileID)
+			msg.FileVersionIDs = append(msg.FileVersionIDs, content.NewEncryptedFileID)
+			msg.NewSize += int(content.NewFileSize)
+			return nil
+		}
 		msg.NewSize = len(msg.Encrypted)
 	default:
 		return merr.Forbidden().Descf("wrong referrer event type %s", e.Type)
 	}
 	return nil
 }
+
+// toggleReaction adds the reaction of the identity on the message
+// or removes it if the identity has already reacted with the same emoji
+func (msg *Message) toggleReaction(emoji, identityID string) {
+	for i, reaction := range msg.Reactions {
+		if reaction.Emoji != emoji {
+			continue
+		}
+		// remove the identity if it has already reacted
+		for j, id := range reaction.IdentityIDs {
+			if id == identityID {
+				reaction.IdentityIDs = append(reaction.IdentityIDs[:j], reaction.IdentityIDs[j+1:]...)
+				reaction.Count = len(reaction.IdentityIDs)
+				// remove the reaction when nobody uses it anymore
+				if reaction.Count == 0 {
+					msg.Reactions = append(msg.Reactions[:i], msg.Reactions[i+1:]...)
+					return
+				}
+				msg.Reactions[i] = reaction
+				return
+			}
+		}
+		reaction.IdentityIDs = append(reaction.IdentityIDs, identityID)
+		reaction.Count = len(reaction.IdentityIDs)
+		msg.Reactions[i] = reaction
+		return
+	}
+	msg.Reactions = append(msg.Reactions, Reaction{Emoji: emoji, Count: 1, IdentityIDs: []string{identityID}})
+}
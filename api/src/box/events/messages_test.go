@@ -0,0 +1,71 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+func TestMessageAddEvent(t *testing.T) {
+	newReaction := func(emoji, senderID string) Event {
+		var json types.JSON
+		err := json.Marshal(MsgReactionContent{Emoji: emoji})
+		assert.Nil(t, err)
+		return Event{Type: "msg.reaction", JSONContent: json, SenderID: senderID}
+	}
+
+	t.Run("reactions are toggled per identity", func(t *testing.T) {
+		msg := Message{LastSenderID: "author-A", NewSize: 42}
+		for _, e := range []Event{
+			newReaction("👍", "member-B"),
+			newReaction("👍", "member-C"),
+			newReaction("🎉", "member-B"),
+			newReaction("👍", "member-B"),
+		} {
+			assert.Nil(t, msg.addEvent(e))
+		}
+		assert.Equal(t, []Reaction{
+			{Emoji: "👍", Count: 1, IdentityIDs: []string{"member-C"}},
+			{Emoji: "🎉", Count: 1, IdentityIDs: []string{"member-B"}},
+		}, msg.Reactions)
+
+		// the last reaction is removed
+		assert.Nil(t, msg.addEvent(newReaction("🎉", "member-B")))
+		assert.Len(t, msg.Reactions, 1)
+
+		// interactions do not modify the message itself
+		assert.Equal(t, "author-A", msg.LastSenderID)
+		assert.Equal(t, 42, msg.NewSize)
+	})
+
+	t.Run("replies are listed", func(t *testing.T) {
+		msg := Message{}
+		assert.Nil(t, msg.addEvent(Event{ID: "reply-A", Type: "msg.reply"}))
+		assert.Nil(t, msg.addEvent(Event{ID: "reply-B", Type: "msg.reply"}))
+		assert.Equal(t, []string{"reply-A", "reply-B"}, msg.ReplyIDs)
+	})
+
+	t.Run("all file versions count in the size", func(t *testing.T) {
+		newVersion := func(fileID string, size int64) Event {
+			var json types.JSON
+			err := json.Marshal(MsgEditContent{NewEncrypted: "enc", NewEncryptedFileID: fileID, NewFileSize: size})
+			assert.Nil(t, err)
+			return Event{Type: "msg.edit", JSONContent: json}
+		}
+
+		msg := Message{Type: "msg.file", FileID: null.StringFrom("file-1"), FileVersionIDs: []string{"file-1"}, NewSize: 100}
+		assert.Nil(t, msg.addEvent(newVersion("file-2", 120)))
+		assert.Nil(t, msg.addEvent(newVersion("file-3", 80)))
+		assert.Equal(t, "file-3", msg.FileID.String)
+		assert.Equal(t, []string{"file-1", "file-2", "file-3"}, msg.FileVersionIDs)
+		assert.Equal(t, 220, msg.OldSize)
+		assert.Equal(t, 300, msg.NewSize)
+
+		// the deletion frees the space of all versions
+		assert.Nil(t, msg.addEvent(Event{Type: "msg.delete"}))
+		assert.Equal(t, 300, msg.OldSize)
+		assert.Equal(t, 0, msg.NewSize)
+	})
+}
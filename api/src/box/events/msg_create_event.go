@@ -18,6 +18,10 @@ func doMessage(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExec
 	if err := MustBeMember(ctx, exec, redConn, e.BoxID, e.SenderID); err != nil {
 		return nil, err
 	}
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
 
 	if e.ReferrerID.Valid {
 		return nil, merr.BadRequest().Desc("referrer id cannot be set").Add("referrer_id", merr.DVForbidden)
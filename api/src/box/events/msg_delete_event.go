@@ -19,6 +19,10 @@ func doDeleteMsg(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextEx
 	if err := MustBeMember(ctx, exec, redConn, e.BoxID, e.SenderID); err != nil {
 		return nil, err
 	}
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
 
 	// check that the event contains a referrer_id
 	if err := checkReferrer(*e); err != nil {
@@ -52,16 +56,9 @@ func doDeleteMsg(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextEx
 	// NOTE: side effect, could be in after handler
 	// (potential) removal of the actual encrypted file (on S3)
 	// is done at the very end because the operation cannot be rolled back
-	if !msg.FileID.IsZero() {
-		isOrphan, err := IsFileOrphan(ctx, exec, msg.FileID.String)
-		if err != nil {
-			return nil, merr.From(err).Desc("checking if file is orphan")
-		}
-		if isOrphan {
-			if err := files.Delete(ctx, exec, filesRepo, msg.FileID.String); err != nil {
-				return nil, merr.From(err).Desc("deleting stored file")
-			}
-		}
+	// all the versions of the file are concerned
+	if err := DeleteOrphanFiles(ctx, exec, filesRepo, msg.FileVersionIDs); err != nil {
+		return nil, merr.From(err).Desc("deleting orphan files")
 	}
 	return &msg, nil
 }
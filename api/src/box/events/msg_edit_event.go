@@ -4,6 +4,7 @@ import (
 	"context"
 
 	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/go-redis/redis/v7"
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
@@ -22,6 +23,12 @@ import (
 type MsgEditContent struct {
 	NewEncrypted string `json:"new_encrypted"`
 	NewPublicKey string `json:"new_public_key"`
+	// NewEncryptedFileID is set on edits of msg.file adding a new version of the file
+	NewEncryptedFileID string `json:"new_encrypted_file_id,omitempty"`
+	// NewFileSize is the size of the new version, set by the server
+	NewFileSize int64 `json:"new_file_size,omitempty"`
+	// Imported is set on edits coming from an imported box
+	Imported *ImportedMetadata `json:"imported,omitempty"`
 }
 
 // Unmarshal a msg.edit content JSON into its typed structure
@@ -34,14 +41,40 @@ func (c MsgEditContent) Validate() error {
 	return v.ValidateStruct(&c,
 		v.Field(&c.NewEncrypted, v.Required, v.Match(format.UnpaddedURLSafeBase64)),
 		v.Field(&c.NewEncrypted, v.Required), // URL-safe base64
+		v.Field(&c.NewEncryptedFileID, is.UUIDv4),
+		v.Field(&c.Imported, v.Nil),
 	)
 }
 
+// NewMsgFileVersion builds a msg.edit event adding a new version of the file of a msg.file
+func NewMsgFileVersion(
+	ctx context.Context,
+	boxID, senderID, referrerID string,
+	fileID string,
+	encContent string, pubKey string,
+) (e Event, err error) {
+	content := MsgEditContent{
+		NewEncrypted:       encContent,
+		NewPublicKey:       pubKey,
+		NewEncryptedFileID: fileID,
+	}
+
+	e, err = newWithAnyContent(etype.Msgedit, &content, boxID, senderID, &referrerID)
+	if err != nil {
+		return e, merr.From(err).Desc("new event")
+	}
+	return e, nil
+}
+
 func doEditMsg(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, redConn *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
 	// check that the current sender is a member of the box
 	if err := MustBeMember(ctx, exec, redConn, e.BoxID, e.SenderID); err != nil {
 		return nil, err
 	}
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
 
 	// check that the event contains a referrer_id
 	if err := checkReferrer(*e); err != nil {
@@ -60,8 +93,32 @@ func doEditMsg(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExec
 	if !msg.DeletedAt.IsZero() {
 		return nil, merr.Gone().Desc("cannot edit a deleted message")
 	}
+
+	var content MsgEditContent
+	if err := content.Unmarshal(e.JSONContent); err != nil {
+		return nil, merr.From(err).Desc("unmarshalling content")
+	}
+	// a msg.file can only be edited by adding a new version of its file
 	if msg.Type == etype.Msgfile {
-		return msg, merr.Forbidden().Descf("cannot edit event type %s", msg.Type)
+		if content.NewEncryptedFileID == "" {
+			return nil, merr.BadRequest().Add("new_encrypted_file_id", merr.DVRequired).
+				Descf("editing a %s requires a new encrypted file", msg.Type)
+		}
+		file, err := files.Get(ctx, exec, content.NewEncryptedFileID)
+		if err != nil {
+			return nil, merr.From(err).Desc("getting new encrypted file")
+		}
+		if err := mustAccessNewFileVersion(ctx, exec, redConn, e.SenderID, file.ID); err != nil {
+			return nil, err
+		}
+		// the size is kept in the content so all versions are counted in the box used space
+		content.NewFileSize = file.Size
+		if err := e.JSONContent.Marshal(content); err != nil {
+			return nil, merr.From(err).Desc("marshalling content")
+		}
+	} else if content.NewEncryptedFileID != "" {
+		return nil, merr.BadRequest().Add("new_encrypted_file_id", merr.DVForbidden).
+			Descf("cannot add a file to event type %s", msg.Type)
 	}
 
 	if err := e.persist(ctx, exec); err != nil {
@@ -74,3 +131,41 @@ func doEditMsg(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExec
 	}
 	return &msg, nil
 }
+
+// mustAccessNewFileVersion checks the sender can add the file as a new version:
+// either the file has just been uploaded and nothing refers it yet,
+// or the sender has access to it through one of their boxes.
+func mustAccessNewFileVersion(ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client, senderID, fileID string) error {
+	// files of deleted messages are orphans too so the references are checked instead
+	savedFiles, err := files.ListSavedFiles(ctx, exec, files.SavedFileFilters{EncryptedFileIDs: []string{fileID}})
+	if err != nil {
+		return merr.From(err).Desc("listing saved files")
+	}
+	fileEvents, err := list(ctx, exec, eventFilters{
+		idOnly: true,
+		eType:  null.StringFrom(etype.Msgfile),
+		fileID: null.StringFrom(fileID),
+	})
+	if err != nil {
+		return merr.From(err).Desc("listing file events")
+	}
+	versionEvents, err := list(ctx, exec, eventFilters{
+		idOnly:        true,
+		eType:         null.StringFrom(etype.Msgedit),
+		versionFileID: null.StringFrom(fileID),
+	})
+	if err != nil {
+		return merr.From(err).Desc("listing version events")
+	}
+	if len(savedFiles) == 0 && len(fileEvents) == 0 && len(versionEvents) == 0 {
+		return nil
+	}
+	hasAccess, err := HasAccessToFile(ctx, exec, redConn, senderID, fileID)
+	if err != nil {
+		return merr.From(err).Desc("checking access to file")
+	}
+	if !hasAccess {
+		return merr.Forbidden().Desc("no access to the new encrypted file").Add("new_encrypted_file_id", merr.DVForbidden)
+	}
+	return nil
+}
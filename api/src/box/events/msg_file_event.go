@@ -11,7 +11,6 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/types"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/format"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
-	"gitlab.misakey.dev/misakey/backend/api/src/sdk/uuid"
 )
 
 // MsgFileContent ...
@@ -19,6 +18,10 @@ type MsgFileContent struct {
 	Encrypted       string `json:"encrypted"`
 	PublicKey       string `json:"public_key"`
 	EncryptedFileID string `json:"encrypted_file_id"`
+	// TTL is an optional number of seconds after which the message expires
+	TTL *int64 `json:"ttl,omitempty"`
+	// Imported is set on messages coming from an imported box
+	Imported *ImportedMetadata `json:"imported,omitempty"`
 
 	// metadata
 	IsSaved bool `json:"is_saved"`
@@ -35,33 +38,32 @@ func (c MsgFileContent) Validate() error {
 		v.Field(&c.Encrypted, v.Required, v.Match(format.UnpaddedURLSafeBase64)),
 		v.Field(&c.PublicKey, v.Required),
 		v.Field(&c.EncryptedFileID, v.Required, is.UUIDv4),
+		v.Field(&c.TTL, v.Min(int64(1)), v.Max(MaxExpirationSeconds)),
+		v.Field(&c.Imported, v.Nil),
 	)
 }
 
-// NewMsgFile ...
-func NewMsgFile(
+// NewMsgFileForID builds a msg.file event describing an already identified encrypted file
+func NewMsgFileForID(
 	ctx context.Context,
 	boxID string, senderID string,
+	fileID string,
 	encContent string, pubKey string,
-) (e Event, fileID string, err error) {
-	// generate a new uuid as a file ID
-	fileID, err = uuid.NewString()
-	if err != nil {
-		return e, "", merr.From(err).Desc("file id")
-	}
-
+	ttl *int64,
+) (e Event, err error) {
 	// build the event content
 	content := MsgFileContent{
 		Encrypted:       encContent,
 		PublicKey:       pubKey,
 		EncryptedFileID: fileID,
+		TTL:             ttl,
 	}
 
 	e, err = newWithAnyContent("msg.file", &content, boxID, senderID, nil)
 	if err != nil {
-		return e, "", merr.From(err).Desc("new event")
+		return e, merr.From(err).Desc("new event")
 	}
-	return e, fileID, nil
+	return e, nil
 }
 
 // GetMsgFile ...
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// MsgReactionContent ...
+type MsgReactionContent struct {
+	Emoji string `json:"emoji"`
+}
+
+// Unmarshal a msg.reaction content JSON into its typed structure
+func (c *MsgReactionContent) Unmarshal(content types.JSON) error {
+	return content.Unmarshal(c)
+}
+
+// Validate a msg.reaction content structure
+func (c MsgReactionContent) Validate() error {
+	return v.ValidateStruct(&c,
+		// some emojis are composed of many runes (skin tones, families...)
+		v.Field(&c.Emoji, v.Required, v.RuneLength(1, 16)),
+	)
+}
+
+// doReaction toggles the reaction of the sender on the referred message:
+// sending twice the same emoji on a message removes the reaction
+func doReaction(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, redConn *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// check that the current sender is a member of the box
+	if err := MustBeMember(ctx, exec, redConn, e.BoxID, e.SenderID); err != nil {
+		return nil, err
+	}
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
+	// check that the event contains a referrer_id
+	if err := checkReferrer(*e); err != nil {
+		return nil, err
+	}
+
+	msg, err := buildMessage(ctx, exec, e.ReferrerID.String)
+	if err != nil {
+		return nil, merr.From(err).Desc("building message")
+	}
+	if msg.BoxID != e.BoxID {
+		return nil, merr.Forbidden().Desc("message from another box").Add("referrer_id", merr.DVForbidden)
+	}
+	// if the message is already deleted, do not go further
+	if !msg.DeletedAt.IsZero() {
+		return nil, merr.Gone().Desc("cannot react to a deleted message")
+	}
+
+	return nil, e.persist(ctx, exec)
+}
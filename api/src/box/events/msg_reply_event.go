@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// doReply creates a message answering the referred message (the parent)
+// the content of a reply has the shape of a msg.text content
+func doReply(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, redConn *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// check that the current sender is a member of the box
+	if err := MustBeMember(ctx, exec, redConn, e.BoxID, e.SenderID); err != nil {
+		return nil, err
+	}
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
+	// check that the event contains a referrer_id
+	if err := checkReferrer(*e); err != nil {
+		return nil, err
+	}
+
+	parent, err := buildMessage(ctx, exec, e.ReferrerID.String)
+	if err != nil {
+		return nil, merr.From(err).Desc("building parent message")
+	}
+	if parent.BoxID != e.BoxID {
+		return nil, merr.Forbidden().Desc("message from another box").Add("referrer_id", merr.DVForbidden)
+	}
+	// if the parent message is already deleted, do not go further
+	if !parent.DeletedAt.IsZero() {
+		return nil, merr.Gone().Desc("cannot reply to a deleted message")
+	}
+
+	if err := e.persist(ctx, exec); err != nil {
+		return nil, err
+	}
+
+	msg, err := buildMessage(ctx, exec, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
@@ -12,6 +12,10 @@ type MsgTextContent struct {
 	Encrypted    string    `json:"encrypted"`
 	PublicKey    string    `json:"public_key"`
 	LastEditedAt null.Time `json:"last_edited_at"`
+	// TTL is an optional number of seconds after which the message expires
+	TTL *int64 `json:"ttl,omitempty"`
+	// Imported is set on messages coming from an imported box
+	Imported *ImportedMetadata `json:"imported,omitempty"`
 }
 
 // Unmarshal ...
@@ -24,5 +28,7 @@ func (c MsgTextContent) Validate() error {
 	return v.ValidateStruct(&c,
 		v.Field(&c.Encrypted, v.Required, v.Match(format.UnpaddedURLSafeBase64)),
 		v.Field(&c.PublicKey, v.Required),
+		v.Field(&c.TTL, v.Min(int64(1)), v.Max(MaxExpirationSeconds)),
+		v.Field(&c.Imported, v.Nil),
 	)
 }
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/cache"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/realtime"
+)
+
+// TypingNotification tells other members of a box that a member is typing
+type TypingNotification struct {
+	IdentityID string `json:"identity_id"`
+	BoxID      string `json:"box_id"`
+	OwnerOrgID string `json:"owner_org_id"`
+}
+
+// PresenceNotification tells co-members of an identity that it has gone online or offline
+type PresenceNotification struct {
+	IdentityID string `json:"identity_id"`
+	Online     bool   `json:"online"`
+}
+
+// NotifyTyping sends a member.typing update to the other members of the box.
+// The identity must be a member of the box.
+// Notifications are throttled: the ones received during the throttle period are ignored.
+func NotifyTyping(ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client, identityID, boxID string) error {
+	if err := MustBeMember(ctx, exec, redConn, boxID, identityID); err != nil {
+		return err
+	}
+
+	allowed, err := realtime.AllowTyping(redConn, identityID, boxID)
+	if err != nil {
+		return merr.From(err).Desc("throttling typing")
+	}
+	if !allowed {
+		return nil
+	}
+
+	memberIDs, err := ListBoxMemberIDs(ctx, exec, redConn, boxID)
+	if err != nil {
+		return merr.From(err).Desc("listing members")
+	}
+
+	createInfo, err := GetCreateInfo(ctx, exec, boxID)
+	if err != nil {
+		return merr.From(err).Desc("getting create info")
+	}
+
+	bu := realtime.Update{
+		Type: "member.typing",
+		Object: TypingNotification{
+			IdentityID: identityID,
+			BoxID:      boxID,
+			OwnerOrgID: createInfo.OwnerOrgID,
+		},
+	}
+	recipientIDs := make([]string, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		if memberID != identityID {
+			recipientIDs = append(recipientIDs, memberID)
+		}
+	}
+	realtime.SendUpdates(ctx, redConn, recipientIDs, &bu)
+	return nil
+}
+
+// NotifyPresence sends a member.presence update to all identities sharing a box with the given identity.
+func NotifyPresence(ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client, identityID string, online bool) error {
+	boxIDsByOrg, err := listBoxIDsForIdentitySortedByOrgAndDatatag(ctx, exec, redConn, identityID)
+	if err != nil {
+		return merr.From(err).Desc("listing identity boxes")
+	}
+
+	var boxIDs []string
+	for _, boxIDsByDatatag := range boxIDsByOrg {
+		for _, datatagBoxIDs := range boxIDsByDatatag {
+			boxIDs = append(boxIDs, datatagBoxIDs...)
+		}
+	}
+	membersByBoxID, err := listMemberIDsByBoxIDs(ctx, exec, redConn, boxIDs)
+	if err != nil {
+		return err
+	}
+
+	// a co-member is notified once even if several boxes are shared
+	uniqueCoMemberIDs := make(map[string]bool)
+	var coMemberIDs []string
+	for _, memberIDs := range membersByBoxID {
+		for _, memberID := range memberIDs {
+			if memberID != identityID && !uniqueCoMemberIDs[memberID] {
+				uniqueCoMemberIDs[memberID] = true
+				coMemberIDs = append(coMemberIDs, memberID)
+			}
+		}
+	}
+
+	bu := realtime.Update{
+		Type: "member.presence",
+		Object: PresenceNotification{
+			IdentityID: identityID,
+			Online:     online,
+		},
+	}
+	realtime.SendUpdates(ctx, redConn, coMemberIDs, &bu)
+	return nil
+}
+
+// listMemberIDsByBoxIDs reads the members caches of all the boxes through a single redis round trip,
+// only the boxes without cache have their members computed one by one.
+func listMemberIDsByBoxIDs(
+	ctx context.Context,
+	exec boil.ContextExecutor, redConn *redis.Client,
+	boxIDs []string,
+) (map[string][]string, error) {
+	membersByBoxID := make(map[string][]string, len(boxIDs))
+	if len(boxIDs) == 0 {
+		return membersByBoxID, nil
+	}
+
+	pipe := redConn.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, len(boxIDs))
+	for i, boxID := range boxIDs {
+		cmds[i] = pipe.SMembers(cache.MemberIDsKeyByBox(boxID))
+	}
+	// errors are handled per command below
+	_, _ = pipe.Exec()
+
+	for i, boxID := range boxIDs {
+		memberIDs, err := cmds[i].Result()
+		if err != nil || len(memberIDs) == 0 {
+			memberIDs, err = ListBoxMemberIDs(ctx, exec, redConn, boxID)
+			if err != nil {
+				return nil, merr.From(err).Descf("listing members of %s", boxID)
+			}
+		}
+		membersByBoxID[boxID] = memberIDs
+	}
+	return membersByBoxID, nil
+}
@@ -0,0 +1,155 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/etype"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/realtime"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/repositories/sqlboiler"
+)
+
+// ReadReceipt tells which event of a box has been lastly read by a member
+// it is stored alongside box settings of the member
+type ReadReceipt struct {
+	IdentityID      string    `json:"identity_id"`
+	BoxID           string    `json:"box_id"`
+	LastReadEventID string    `json:"last_read_event_id"`
+	LastReadAt      time.Time `json:"last_read_at"`
+}
+
+// MarkAsRead stores the read event as the last one read by the identity in the box.
+// The last event of the box is considered if no event id is received.
+// Other members of the box are notified about the new read receipt.
+func MarkAsRead(
+	ctx context.Context,
+	exec boil.ContextExecutor, redConn *redis.Client,
+	identityID, boxID string, eventID *string,
+) error {
+	if err := MustBeMember(ctx, exec, redConn, boxID, identityID); err != nil {
+		return err
+	}
+
+	// 1. retrieve the read event: only events members can see can be read,
+	// the most recent one is taken if no event id is received
+	filters := eventFilters{
+		boxID:  null.StringFrom(boxID),
+		eTypes: etype.MembersCanSee,
+	}
+	if eventID != nil {
+		filters.id = null.StringFromPtr(eventID)
+	}
+	readEvent, err := get(ctx, exec, filters)
+	if err != nil {
+		return merr.From(err).Desc("getting read event")
+	}
+
+	// 2. never go back in time: ignore events older than the current last read one
+	current, err := sqlboiler.BoxSettings(
+		sqlboiler.BoxSettingWhere.BoxID.EQ(boxID),
+		sqlboiler.BoxSettingWhere.IdentityID.EQ(identityID),
+	).One(ctx, exec)
+	if err != nil && err != sql.ErrNoRows {
+		return merr.From(err).Desc("getting box setting")
+	}
+	if err == nil && current.LastReadEventID.Valid {
+		lastRead, err := get(ctx, exec, eventFilters{
+			id:    current.LastReadEventID,
+			boxID: null.StringFrom(boxID),
+		})
+		if err != nil && !merr.IsANotFound(err) {
+			return merr.From(err).Desc("getting last read event")
+		}
+		if err == nil && !readEvent.CreatedAt.After(lastRead.CreatedAt) {
+			return nil
+		}
+	}
+
+	// 3. store the read receipt
+	receipt := ReadReceipt{
+		IdentityID:      identityID,
+		BoxID:           boxID,
+		LastReadEventID: readEvent.ID,
+		LastReadAt:      time.Now(),
+	}
+	toUpsert := sqlboiler.BoxSetting{
+		IdentityID:      receipt.IdentityID,
+		BoxID:           receipt.BoxID,
+		LastReadEventID: null.StringFrom(receipt.LastReadEventID),
+		LastReadAt:      null.TimeFrom(receipt.LastReadAt),
+	}
+	// only the read receipt columns are updated to not override other settings
+	updateColumns := boil.Whitelist(
+		sqlboiler.BoxSettingColumns.LastReadEventID,
+		sqlboiler.BoxSettingColumns.LastReadAt,
+		sqlboiler.BoxSettingColumns.UpdatedAt,
+	)
+	conflictColumns := []string{sqlboiler.BoxSettingColumns.BoxID, sqlboiler.BoxSettingColumns.IdentityID}
+	if err := toUpsert.Upsert(ctx, exec, true, conflictColumns, updateColumns, boil.Infer()); err != nil {
+		return merr.From(err).Desc("upserting read receipt")
+	}
+
+	// 4. notify other members
+	return notifyRead(ctx, exec, redConn, receipt)
+}
+
+// ListReadReceipts of a box
+// it returns a map[identityID]ReadReceipt
+func ListReadReceipts(ctx context.Context, exec boil.ContextExecutor, boxID string) (map[string]ReadReceipt, error) {
+	mods := []qm.QueryMod{
+		sqlboiler.BoxSettingWhere.BoxID.EQ(boxID),
+		sqlboiler.BoxSettingWhere.LastReadEventID.IsNotNull(),
+	}
+	dbBoxSettings, err := sqlboiler.BoxSettings(mods...).All(ctx, exec)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing box settings")
+	}
+
+	receipts := make(map[string]ReadReceipt, len(dbBoxSettings))
+	for _, boxSetting := range dbBoxSettings {
+		receipts[boxSetting.IdentityID] = ReadReceipt{
+			IdentityID:      boxSetting.IdentityID,
+			BoxID:           boxSetting.BoxID,
+			LastReadEventID: boxSetting.LastReadEventID.String,
+			LastReadAt:      boxSetting.LastReadAt.Time,
+		}
+	}
+	return receipts, nil
+}
+
+// send a member.read realtime update to all members of the box except the reader
+func notifyRead(ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client, receipt ReadReceipt) error {
+	memberIDs, err := ListBoxMemberIDs(ctx, exec, redConn, receipt.BoxID)
+	if err != nil {
+		return merr.From(err).Desc("notifying member: listing members")
+	}
+
+	createInfo, err := GetCreateInfo(ctx, exec, receipt.BoxID)
+	if err != nil {
+		return merr.From(err).Desc("getting create info")
+	}
+
+	bu := realtime.Update{
+		Type: "member.read",
+		Object: struct {
+			ReadReceipt
+			OwnerOrgID string `json:"owner_org_id"`
+		}{
+			receipt, createInfo.OwnerOrgID,
+		},
+	}
+	for _, memberID := range memberIDs {
+		if memberID != receipt.IdentityID {
+			realtime.SendUpdate(ctx, redConn, memberID, &bu)
+		}
+	}
+	return nil
+}
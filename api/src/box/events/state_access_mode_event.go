@@ -42,5 +42,10 @@ func doStateAccessMode(ctx context.Context, e *Event, _ null.JSON, exec boil.Con
 	if err := MustBeAdmin(ctx, exec, e.BoxID, e.SenderID); err != nil {
 		return nil, merr.From(err).Desc("checking admin")
 	}
+
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
 	return nil, e.persist(ctx, exec)
 }
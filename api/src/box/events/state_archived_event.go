@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/etype"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// ArchivedContent ...
+type ArchivedContent struct {
+	Value null.Bool `json:"value"`
+}
+
+// Unmarshal ...
+func (c *ArchivedContent) Unmarshal(content types.JSON) error {
+	return content.Unmarshal(c)
+}
+
+// Validate ...
+func (c ArchivedContent) Validate() error {
+	return v.ValidateStruct(&c,
+		// the value must be explicit: a false value unarchives the box
+		v.Field(&c.Value, v.NotNil),
+	)
+}
+
+func doStateArchived(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, _ *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// check accesses
+	if err := MustBeAdmin(ctx, exec, e.BoxID, e.SenderID); err != nil {
+		return nil, merr.From(err).Desc("checking admin")
+	}
+
+	c := ArchivedContent{}
+	if err := c.Unmarshal(e.JSONContent); err != nil {
+		return nil, merr.From(err).Descf("unmarshaling %s content", e.Type)
+	}
+	archived, err := isArchived(ctx, exec, e.BoxID)
+	if err != nil {
+		return nil, err
+	}
+	if archived == c.Value.Bool {
+		return nil, merr.Conflict().Desc("archived state already set").Add("value", merr.DVConflict)
+	}
+	return nil, e.persist(ctx, exec)
+}
+
+// MustNotBeArchived returns a forbidden error with a locked detail on the box id
+// if the box has been archived, meaning it is read-only.
+func MustNotBeArchived(ctx context.Context, exec boil.ContextExecutor, boxID string) error {
+	archived, err := isArchived(ctx, exec, boxID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return merr.Forbidden().Desc("box is archived").Add("box_id", merr.DVLocked)
+	}
+	return nil
+}
+
+// isArchived returns the value set by the most recent state.archived event of the box
+func isArchived(ctx context.Context, exec boil.ContextExecutor, boxID string) (bool, error) {
+	// get() always get last event corresponding to the query
+	archivedEvent, err := get(ctx, exec, eventFilters{
+		boxID: null.StringFrom(boxID),
+		eType: null.StringFrom(etype.Statearchived),
+	})
+	if merr.IsANotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, merr.From(err).Desc("getting last archived event")
+	}
+
+	c := ArchivedContent{}
+	if err := c.Unmarshal(archivedEvent.JSONContent); err != nil {
+		return false, merr.From(err).Descf("unmarshaling %s content", etype.Statearchived)
+	}
+	return c.Value.Bool, nil
+}
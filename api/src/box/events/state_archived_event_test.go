@@ -0,0 +1,32 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+func TestArchivedContentValidation(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		valid   bool
+	}{
+		"archive":       {content: `{"value":true}`, valid: true},
+		"unarchive":     {content: `{"value":false}`, valid: true},
+		"missing value": {content: `{}`, valid: false},
+		"null value":    {content: `{"value":null}`, valid: false},
+		"wrong type":    {content: `{"value":"yes"}`, valid: false},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			e := Event{Type: "state.archived", JSONContent: types.JSON(test.content)}
+			err := bindAndValidateContent(&e)
+			if test.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// DescriptionContent ...
+type DescriptionContent struct {
+	Value string `json:"value"`
+}
+
+// Unmarshal ...
+func (c *DescriptionContent) Unmarshal(content types.JSON) error {
+	return content.Unmarshal(c)
+}
+
+// Validate ...
+func (c DescriptionContent) Validate() error {
+	return v.ValidateStruct(&c,
+		// an empty value removes the description
+		v.Field(&c.Value, v.Length(0, 500)),
+	)
+}
+
+func doStateDescription(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, _ *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// check accesses
+	if err := MustBeAdmin(ctx, exec, e.BoxID, e.SenderID); err != nil {
+		return nil, merr.From(err).Desc("checking admin")
+	}
+
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+	return nil, e.persist(ctx, exec)
+}
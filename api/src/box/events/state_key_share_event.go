@@ -23,6 +23,11 @@ func doStateKeyShare(ctx context.Context, e *Event, extraJSON null.JSON, exec bo
 		return nil, merr.From(err).Desc("checking admin")
 	}
 
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+
 	// there is no "content" for this kind of event,
 	// only "extra"
 
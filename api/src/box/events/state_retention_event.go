@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// RetentionContent ...
+type RetentionContent struct {
+	// Value is the number of seconds messages are kept in the box
+	Value int64 `json:"value"`
+}
+
+// Unmarshal ...
+func (c *RetentionContent) Unmarshal(content types.JSON) error {
+	return content.Unmarshal(c)
+}
+
+// Validate ...
+func (c RetentionContent) Validate() error {
+	return v.ValidateStruct(&c,
+		// a zero value removes the retention policy
+		v.Field(&c.Value, v.Min(int64(0)), v.Max(MaxExpirationSeconds)),
+	)
+}
+
+func doStateRetention(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, _ *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// check accesses
+	if err := MustBeAdmin(ctx, exec, e.BoxID, e.SenderID); err != nil {
+		return nil, merr.From(err).Desc("checking admin")
+	}
+
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+	return nil, e.persist(ctx, exec)
+}
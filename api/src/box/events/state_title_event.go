@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/etype"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// TitleContent ...
+type TitleContent struct {
+	Value string `json:"value"`
+}
+
+// Unmarshal ...
+func (c *TitleContent) Unmarshal(content types.JSON) error {
+	return content.Unmarshal(c)
+}
+
+// Validate ...
+func (c TitleContent) Validate() error {
+	return v.ValidateStruct(&c,
+		// same rule as the title set on box creation
+		v.Field(&c.Value, v.Required, v.Length(1, 50)),
+	)
+}
+
+func doStateTitle(ctx context.Context, e *Event, _ null.JSON, exec boil.ContextExecutor, _ *redis.Client, _ *IdentityMapper, _ external.CryptoRepo, _ files.FileStorageRepo) (Metadata, error) {
+	// check accesses
+	if err := MustBeAdmin(ctx, exec, e.BoxID, e.SenderID); err != nil {
+		return nil, merr.From(err).Desc("checking admin")
+	}
+
+	// archived boxes are read-only
+	if err := MustNotBeArchived(ctx, exec, e.BoxID); err != nil {
+		return nil, err
+	}
+	return nil, e.persist(ctx, exec)
+}
+
+// getLastTitle returns the title set by the most recent state.title event of the box
+// the returned value is invalid if the title has never been updated since the box creation
+func getLastTitle(ctx context.Context, exec boil.ContextExecutor, boxID string) (null.String, error) {
+	// get() always get last event corresponding to the query
+	titleEvent, err := get(ctx, exec, eventFilters{
+		boxID: null.StringFrom(boxID),
+		eType: null.StringFrom(etype.Statetitle),
+	})
+	if merr.IsANotFound(err) {
+		return null.String{}, nil
+	}
+	if err != nil {
+		return null.String{}, merr.From(err).Desc("getting last title event")
+	}
+
+	c := TitleContent{}
+	if err := c.Unmarshal(titleEvent.JSONContent); err != nil {
+		return null.String{}, merr.From(err).Descf("unmarshaling %s content", etype.Statetitle)
+	}
+	return null.StringFrom(c.Value), nil
+}
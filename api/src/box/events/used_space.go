@@ -4,8 +4,14 @@ import (
 	"context"
 
 	"github.com/go-redis/redis/v7"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/types"
 
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events/etype"
 	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
 	"gitlab.misakey.dev/misakey/backend/api/src/box/quota"
 )
@@ -21,3 +27,91 @@ func computeUsedSpace(ctx context.Context, e *Event, exec boil.ContextExecutor,
 
 	return quota.UpdateBoxUsedSpace(ctx, exec, e.BoxID, int64(msg.NewSize), int64(msg.OldSize))
 }
+
+// ListBoxIDs returns the ids of all existing boxes
+func ListBoxIDs(ctx context.Context, exec boil.ContextExecutor) ([]string, error) {
+	createEvents, err := list(ctx, exec, eventFilters{
+		boxIDOnly: true,
+		eType:     null.StringFrom(etype.Create),
+	})
+	if err != nil {
+		return nil, err
+	}
+	boxIDs := make([]string, len(createEvents))
+	for i, e := range createEvents {
+		boxIDs[i] = e.BoxID
+	}
+	return boxIDs, nil
+}
+
+// ComputeBoxUsedSpace by summing the size of all messages of the box, considering their last state.
+func ComputeBoxUsedSpace(ctx context.Context, exec boil.ContextExecutor, boxID string) (int64, error) {
+	msgEvents, err := list(ctx, exec, eventFilters{
+		idOnly: true,
+		boxID:  null.StringFrom(boxID),
+		eTypes: etype.Messages,
+	})
+	if err != nil {
+		return 0, merr.From(err).Desc("listing messages")
+	}
+
+	var usedSpace int64
+	for _, e := range msgEvents {
+		msg, err := buildMessage(ctx, exec, e.ID)
+		if err != nil {
+			return 0, merr.From(err).Descf("building message %s", e.ID)
+		}
+		usedSpace += int64(msg.NewSize)
+	}
+	return usedSpace, nil
+}
+
+// StoredUsedSpaceFilters selects the encrypted files whose stored data is counted
+type StoredUsedSpaceFilters struct {
+	// BoxIDs whose live messages count with the last version of their file
+	BoxIDs []string
+	// IdentityID whose saved files count
+	IdentityID null.String
+	// FileIDs counting in addition
+	FileIDs []string
+}
+
+// the encrypted files are the last versions of the live messages of the boxes ($1),
+// the saved files of the identity ($2) and the additional files ($3),
+// a msg.edit referring the msg.file it adds a version to
+const storedUsedSpaceQuery = `
+SELECT COALESCE(SUM(stored.size), 0) AS total FROM (
+	SELECT DISTINCT ON (encrypted_file.storage_key) encrypted_file.size
+	FROM encrypted_file
+	WHERE encrypted_file.id IN (
+		SELECT DISTINCT ON (COALESCE(msg.referrer_id, msg.id))
+			CAST(COALESCE(msg.content->>'new_encrypted_file_id', msg.content->>'encrypted_file_id') AS UUID)
+		FROM event AS msg
+		WHERE msg.box_id = ANY(CAST($1 AS UUID[]))
+		AND (msg.type = $4 OR (msg.type = $5 AND msg.content->>'new_encrypted_file_id' IS NOT NULL))
+		AND NOT EXISTS (
+			SELECT 1 FROM event AS ref
+			WHERE ref.referrer_id = COALESCE(msg.referrer_id, msg.id) AND ref.type = $6
+		)
+		ORDER BY COALESCE(msg.referrer_id, msg.id), msg.created_at DESC
+	)
+	OR encrypted_file.id IN (SELECT saved_file.encrypted_file_id FROM saved_file WHERE saved_file.identity_id = $2)
+	OR encrypted_file.id = ANY(CAST($3 AS UUID[]))
+	ORDER BY encrypted_file.storage_key
+) AS stored;`
+
+// ComputeStoredUsedSpace sums the size of the stored data of the selected encrypted files.
+// Deduplicated files share their stored data which is then counted only once.
+func ComputeStoredUsedSpace(ctx context.Context, exec boil.ContextExecutor, filters StoredUsedSpaceFilters) (int64, error) {
+	var usedSpace struct {
+		Total int64 `boil:"total"`
+	}
+	err := queries.Raw(storedUsedSpaceQuery,
+		types.StringArray(filters.BoxIDs), filters.IdentityID, types.StringArray(filters.FileIDs),
+		etype.Msgfile, etype.Msgedit, etype.Msgdelete,
+	).Bind(ctx, exec, &usedSpace)
+	if err != nil {
+		return 0, err
+	}
+	return usedSpace.Total, nil
+}
@@ -1,16 +1,20 @@
 package files
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"sort"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/rs/zerolog/log"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/ms3"
 )
 
 // FileAmazonS3 ...
@@ -23,10 +27,9 @@ type FileAmazonS3 struct {
 }
 
 // NewFileAmazonS3 init an S3 session
-func NewFileAmazonS3(region, bucket string) *FileAmazonS3 {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(region)},
-	)
+// any S3-compatible storage can be used through the configuration
+func NewFileAmazonS3(cfg ms3.Config, bucket string) *FileAmazonS3 {
+	sess, err := ms3.NewSession(cfg)
 	if err != nil {
 		log.Fatal().Msg("could not initiate AWS S3 avatar bucket connection")
 	}
@@ -69,6 +72,100 @@ func (s *FileAmazonS3) Download(ctx context.Context, fileID string) (io.Reader,
 	return rawObj.Body, nil
 }
 
+// DownloadRange of data from amazon S3 at {bucket}/{fileID} using the Range header
+func (s *FileAmazonS3) DownloadRange(ctx context.Context, fileID string, offset, length int64) (io.ReadCloser, error) {
+	getObj := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fileID),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	}
+	rawObj, err := s.session.GetObjectWithContext(ctx, getObj)
+	if err != nil {
+		return nil, merr.Internal().Descf("unable to download range of object %s from bucket %q, %v", fileID, s.bucket, err)
+	}
+	return rawObj.Body, nil
+}
+
+// PresignUpload returns a pre-signed url on which the object of the given size can be put
+// at {bucket}/{fileID}.pending
+func (s *FileAmazonS3) PresignUpload(ctx context.Context, fileID string, size int64, expiration time.Duration) (string, error) {
+	req, _ := s.session.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(PendingKey(fileID)),
+		ContentLength: aws.Int64(size),
+	})
+	signedURL, err := req.Presign(expiration)
+	if err != nil {
+		return "", merr.Internal().Descf("unable to presign upload of %q to %q, %v", fileID, s.bucket, err)
+	}
+	return signedURL, nil
+}
+
+// ConfirmUpload copies the object at {bucket}/{fileID}.pending to {bucket}/{fileID} then deletes it
+func (s *FileAmazonS3) ConfirmUpload(ctx context.Context, fileID string) error {
+	_, err := s.session.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		CopySource: aws.String(s.bucket + "/" + PendingKey(fileID)),
+		Key:        aws.String(fileID),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return merr.NotFound().Desc(err.Error())
+		}
+		return merr.Internal().Descf("unable to copy pending %q in %q, %v", fileID, s.bucket, err)
+	}
+	return s.Delete(ctx, PendingKey(fileID))
+}
+
+// PresignDownload returns a pre-signed url from which the object at {bucket}/{fileID} can be got
+func (s *FileAmazonS3) PresignDownload(ctx context.Context, fileID string, expiration time.Duration) (string, error) {
+	req, _ := s.session.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fileID),
+	})
+	signedURL, err := req.Presign(expiration)
+	if err != nil {
+		return "", merr.Internal().Descf("unable to presign download of %q from %q, %v", fileID, s.bucket, err)
+	}
+	return signedURL, nil
+}
+
+// Size of the object at {bucket}/{fileID}
+func (s *FileAmazonS3) Size(ctx context.Context, fileID string) (int64, error) {
+	output, err := s.session.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fileID),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return 0, merr.NotFound().Desc(err.Error())
+		}
+		return 0, merr.Internal().Descf("unable to head object %q from %q, %v", fileID, s.bucket, err)
+	}
+	return aws.Int64Value(output.ContentLength), nil
+}
+
+// List all objects of the bucket
+func (s *FileAmazonS3) List(ctx context.Context) ([]StoredFile, error) {
+	var stored []StoredFile
+	err := s.session.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range page.Contents {
+			stored = append(stored, StoredFile{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, merr.Internal().Descf("unable to list objects of %q, %v", s.bucket, err)
+	}
+	return stored, nil
+}
+
 // Delete data from s3 at {bucket}/{fileID}
 func (s *FileAmazonS3) Delete(ctx context.Context, fileID string) error {
 	delObj := &s3.DeleteObjectInput{
@@ -83,3 +180,146 @@ func (s *FileAmazonS3) Delete(ctx context.Context, fileID string) error {
 	}
 	return nil
 }
+
+// StartChunkedUpload creates a multipart upload on amazon s3 at {bucket}/{fileID}
+// and returns its upload id
+func (s *FileAmazonS3) StartChunkedUpload(ctx context.Context, fileID string) (string, error) {
+	output, err := s.session.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fileID),
+	})
+	if err != nil {
+		return "", merr.Internal().Descf("unable to start multipart upload of %q to %q, %v", fileID, s.bucket, err)
+	}
+	return aws.StringValue(output.UploadId), nil
+}
+
+// UploadChunk uploads a part of the multipart upload
+func (s *FileAmazonS3) UploadChunk(ctx context.Context, fileID, uploadID string, number int, data []byte) error {
+	_, err := s.session.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(fileID),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int64(int64(number)),
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return merr.Internal().Descf("unable to upload part %d of %q to %q, %v", number, fileID, s.bucket, err)
+	}
+	return nil
+}
+
+// ListChunks returns the sorted numbers of the parts already uploaded
+func (s *FileAmazonS3) ListChunks(ctx context.Context, fileID, uploadID string) ([]int, error) {
+	parts, err := s.listParts(ctx, fileID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		numbers[i] = int(aws.Int64Value(part.PartNumber))
+	}
+	return numbers, nil
+}
+
+// CompleteChunkedUpload assembles all uploaded parts into the final object
+func (s *FileAmazonS3) CompleteChunkedUpload(ctx context.Context, fileID, uploadID string) error {
+	parts, err := s.listParts(ctx, fileID, uploadID)
+	if err != nil {
+		return err
+	}
+	completedParts := make([]*s3.CompletedPart, len(parts))
+	for i, part := range parts {
+		completedParts[i] = &s3.CompletedPart{
+			ETag:       part.ETag,
+			PartNumber: part.PartNumber,
+		}
+	}
+	_, err = s.session.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(fileID),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completedParts},
+	})
+	if err != nil {
+		return merr.Internal().Descf("unable to complete multipart upload of %q to %q, %v", fileID, s.bucket, err)
+	}
+	return nil
+}
+
+// AbortChunkedUpload aborts the multipart upload and frees its parts
+func (s *FileAmazonS3) AbortChunkedUpload(ctx context.Context, fileID, uploadID string) error {
+	_, err := s.session.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(fileID),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchUpload {
+			return merr.NotFound().Desc(err.Error())
+		}
+		return merr.Internal().Descf("unable to abort multipart upload of %q to %q, %v", fileID, s.bucket, err)
+	}
+	return nil
+}
+
+// ListChunkedUploads returns the multipart uploads in progress in the bucket,
+// their last modification being the upload of their last part
+func (s *FileAmazonS3) ListChunkedUploads(ctx context.Context) ([]ChunkedUpload, error) {
+	var uploads []ChunkedUpload
+	err := s.session.ListMultipartUploadsPagesWithContext(ctx, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(s.bucket),
+	}, func(page *s3.ListMultipartUploadsOutput, _ bool) bool {
+		for _, upload := range page.Uploads {
+			uploads = append(uploads, ChunkedUpload{
+				FileID:       aws.StringValue(upload.Key),
+				UploadID:     aws.StringValue(upload.UploadId),
+				LastModified: aws.TimeValue(upload.Initiated),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, merr.Internal().Descf("unable to list multipart uploads of %q, %v", s.bucket, err)
+	}
+	for i, upload := range uploads {
+		parts, err := s.listParts(ctx, upload.FileID, upload.UploadID)
+		if err != nil {
+			// the upload might have been completed or aborted meanwhile
+			if merr.IsANotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, part := range parts {
+			if lastModified := aws.TimeValue(part.LastModified); lastModified.After(uploads[i].LastModified) {
+				uploads[i].LastModified = lastModified
+			}
+		}
+	}
+	return uploads, nil
+}
+
+// listParts of a multipart upload sorted by part number
+func (s *FileAmazonS3) listParts(ctx context.Context, fileID, uploadID string) ([]*s3.Part, error) {
+	var parts []*s3.Part
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(fileID),
+		UploadId: aws.String(uploadID),
+	}
+	err := s.session.ListPartsPagesWithContext(ctx, input, func(page *s3.ListPartsOutput, _ bool) bool {
+		parts = append(parts, page.Parts...)
+		return true
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchUpload {
+			return nil, merr.NotFound().Desc(err.Error())
+		}
+		return nil, merr.Internal().Descf("unable to list parts of %q in %q, %v", fileID, s.bucket, err)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return aws.Int64Value(parts[i].PartNumber) < aws.Int64Value(parts[j].PartNumber)
+	})
+	return parts, nil
+}
@@ -2,10 +2,19 @@ package files
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 )
@@ -13,35 +22,36 @@ import (
 // FileSystem contains the files storage location
 type FileSystem struct {
 	location string
+
+	// signed urls are served by the api on this base url, signed using the key
+	url        string
+	signingKey []byte
 }
 
 // NewFileSystem constructor
 // /!\ NOT SAFE TO USE IN PRODUCTION
-func NewFileSystem(location string) *FileSystem {
+func NewFileSystem(location, url, signingKey string) *FileSystem {
 	// create files directory
 	if _, err := os.Stat(location); os.IsNotExist(err) {
 		_ = os.Mkdir(location, os.ModePerm)
 	}
 	return &FileSystem{
-		location: location,
+		location:   location,
+		url:        url,
+		signingKey: []byte(signingKey),
 	}
 }
 
 // Upload an file in file system directory and return its path
 func (fs *FileSystem) Upload(ctx context.Context, fileID string, data io.Reader,
 ) error {
-	body, err := ioutil.ReadAll(data)
-	if err != nil {
-		return err
-	}
-
 	filePath := path.Join(fs.location, fileID)
 	f, err := os.Create(filePath)
 	if err != nil {
 		return err
 	}
 
-	if _, err := f.Write(body); err != nil {
+	if _, err := io.Copy(f, data); err != nil {
 		f.Close()
 		return err
 	}
@@ -55,6 +65,25 @@ func (fs *FileSystem) Download(ctx context.Context, fileID string) (io.Reader, e
 	return os.Open(filePath)
 }
 
+// DownloadRange of a file from local storage seeking to the offset
+func (fs *FileSystem) DownloadRange(ctx context.Context, fileID string, offset, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(path.Join(fs.location, fileID))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return limitedFile{Reader: io.LimitReader(f, length), Closer: f}, nil
+}
+
+// limitedFile reads a limited part of a file and closes the file
+type limitedFile struct {
+	io.Reader
+	io.Closer
+}
+
 // Delete a file from the file system
 func (fs *FileSystem) Delete(ctx context.Context, fileID string) error {
 	path := path.Join(fs.location, fileID)
@@ -66,3 +95,200 @@ func (fs *FileSystem) Delete(ctx context.Context, fileID string) error {
 	}
 	return os.Remove(path)
 }
+
+// StartChunkedUpload creates a directory to store the parts of the file
+// the file id is used as upload id since there is no concurrent upload for a file
+func (fs *FileSystem) StartChunkedUpload(ctx context.Context, fileID string) (string, error) {
+	if err := os.Mkdir(fs.partsLocation(fileID), os.ModePerm); err != nil {
+		return "", err
+	}
+	return fileID, nil
+}
+
+// UploadChunk stores the part in the parts directory of the file
+func (fs *FileSystem) UploadChunk(ctx context.Context, fileID, uploadID string, number int, data []byte) error {
+	if _, err := os.Stat(fs.partsLocation(uploadID)); os.IsNotExist(err) {
+		return merr.NotFound().Desc(err.Error())
+	}
+	return ioutil.WriteFile(path.Join(fs.partsLocation(uploadID), strconv.Itoa(number)), data, 0600)
+}
+
+// ListChunks returns the sorted numbers of the parts already stored
+func (fs *FileSystem) ListChunks(ctx context.Context, fileID, uploadID string) ([]int, error) {
+	infos, err := ioutil.ReadDir(fs.partsLocation(uploadID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, merr.NotFound().Desc(err.Error())
+		}
+		return nil, err
+	}
+	numbers := make([]int, 0, len(infos))
+	for _, info := range infos {
+		number, err := strconv.Atoi(info.Name())
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// CompleteChunkedUpload appends all stored parts into the final file then removes them
+func (fs *FileSystem) CompleteChunkedUpload(ctx context.Context, fileID, uploadID string) error {
+	numbers, err := fs.ListChunks(ctx, fileID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path.Join(fs.location, fileID))
+	if err != nil {
+		return err
+	}
+	for _, number := range numbers {
+		if err := fs.appendPart(f, path.Join(fs.partsLocation(uploadID), strconv.Itoa(number))); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(fs.partsLocation(uploadID))
+}
+
+// AbortChunkedUpload removes the stored parts
+func (fs *FileSystem) AbortChunkedUpload(ctx context.Context, fileID, uploadID string) error {
+	if _, err := os.Stat(fs.partsLocation(uploadID)); err != nil {
+		if os.IsNotExist(err) {
+			return merr.NotFound().Desc(err.Error())
+		}
+		return err
+	}
+	return os.RemoveAll(fs.partsLocation(uploadID))
+}
+
+// ListChunkedUploads returns the parts directories, modified on each part upload
+func (fs *FileSystem) ListChunkedUploads(ctx context.Context) ([]ChunkedUpload, error) {
+	infos, err := ioutil.ReadDir(fs.location)
+	if err != nil {
+		return nil, err
+	}
+	var uploads []ChunkedUpload
+	for _, info := range infos {
+		if !info.IsDir() || !strings.HasSuffix(info.Name(), partsSuffix) {
+			continue
+		}
+		// the file id is used as upload id
+		fileID := strings.TrimSuffix(info.Name(), partsSuffix)
+		uploads = append(uploads, ChunkedUpload{
+			FileID:       fileID,
+			UploadID:     fileID,
+			LastModified: info.ModTime(),
+		})
+	}
+	return uploads, nil
+}
+
+const partsSuffix = ".parts"
+
+func (fs *FileSystem) partsLocation(uploadID string) string {
+	return path.Join(fs.location, uploadID+partsSuffix)
+}
+
+func (fs *FileSystem) appendPart(dst io.Writer, partPath string) error {
+	part, err := os.Open(partPath)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+	_, err = io.Copy(dst, part)
+	return err
+}
+
+// PresignUpload returns a signed url on which the file of the given size can be put,
+// the signed url handler stores it on its pending key
+func (fs *FileSystem) PresignUpload(ctx context.Context, fileID string, size int64, expiration time.Duration) (string, error) {
+	return fs.signURL("PUT", fileID, size, time.Now().Add(expiration).Unix()), nil
+}
+
+// ConfirmUpload moves the file from its pending key
+func (fs *FileSystem) ConfirmUpload(ctx context.Context, fileID string) error {
+	if err := os.Rename(path.Join(fs.location, PendingKey(fileID)), path.Join(fs.location, fileID)); err != nil {
+		if os.IsNotExist(err) {
+			return merr.NotFound().Desc(err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// PresignDownload returns a signed url from which the file can be got
+func (fs *FileSystem) PresignDownload(ctx context.Context, fileID string, expiration time.Duration) (string, error) {
+	return fs.signURL("GET", fileID, 0, time.Now().Add(expiration).Unix()), nil
+}
+
+// Size of a stored file
+func (fs *FileSystem) Size(ctx context.Context, fileID string) (int64, error) {
+	info, err := os.Stat(path.Join(fs.location, fileID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, merr.NotFound().Desc(err.Error())
+		}
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// List the stored files, ignoring the parts directories of chunked uploads
+func (fs *FileSystem) List(ctx context.Context) ([]StoredFile, error) {
+	infos, err := ioutil.ReadDir(fs.location)
+	if err != nil {
+		return nil, err
+	}
+	stored := make([]StoredFile, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		stored = append(stored, StoredFile{
+			Key:          info.Name(),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+	return stored, nil
+}
+
+// VerifySignedURL checks the signature of an url built by PresignUpload or PresignDownload
+// for the given method is valid and not expired
+func (fs *FileSystem) VerifySignedURL(method, fileID string, size, expires int64, signature string) error {
+	if len(fs.signingKey) == 0 {
+		return merr.NotFound().Desc("signed urls are not configured")
+	}
+	if time.Now().Unix() > expires {
+		return merr.Forbidden().Add("expires", merr.DVExpired)
+	}
+	expected := fs.sign(method, fileID, size, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return merr.Forbidden().Add("signature", merr.DVInvalid)
+	}
+	return nil
+}
+
+func (fs *FileSystem) signURL(method, fileID string, size, expires int64) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	if size != 0 {
+		query.Set("size", strconv.FormatInt(size, 10))
+	}
+	query.Set("signature", fs.sign(method, fileID, size, expires))
+	return fmt.Sprintf("%s/%s/signed?%s", fs.url, fileID, query.Encode())
+}
+
+func (fs *FileSystem) sign(method, fileID string, size, expires int64) string {
+	mac := hmac.New(sha256.New, fs.signingKey)
+	// writing in a hash never returns an error
+	_, _ = mac.Write([]byte(fmt.Sprintf("%s:%s:%d:%d", method, fileID, size, expires)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
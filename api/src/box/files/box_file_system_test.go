@@ -0,0 +1,40 @@
+package files
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+)
+
+func TestFileSystemConfirmUpload(t *testing.T) {
+	ctx := context.Background()
+	location, err := ioutil.TempDir("", "files")
+	assert.NoError(t, err)
+	defer os.RemoveAll(location)
+	fs := NewFileSystem(location, "http://localhost/files", "key")
+	fileID := "c0ab1a10-5d0f-4b89-a2a8-7ba0b6d6c7a4"
+
+	// nothing has been put on the pending key
+	err = fs.ConfirmUpload(ctx, fileID)
+	assert.True(t, merr.IsANotFound(err))
+
+	assert.NoError(t, fs.Upload(ctx, PendingKey(fileID), strings.NewReader("confirmed")))
+	_, err = fs.Size(ctx, fileID)
+	assert.True(t, merr.IsANotFound(err))
+	assert.NoError(t, fs.ConfirmUpload(ctx, fileID))
+
+	// the upload url can still be used but it does not overwrite the confirmed file
+	assert.NoError(t, fs.Upload(ctx, PendingKey(fileID), strings.NewReader("overwritten")))
+	reader, err := fs.Download(ctx, fileID)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "confirmed", string(data))
+	reader.(*os.File).Close()
+}
@@ -0,0 +1,106 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/uuid"
+)
+
+// DirectUpload is an encrypted file put by the client directly on the storage
+// using a pre-signed url, waiting for the confirmation of its upload
+type DirectUpload struct {
+	EncryptedFileID string    `json:"encrypted_file_id"`
+	BoxID           string    `json:"box_id"`
+	IdentityID      string    `json:"identity_id"`
+	Size            int64     `json:"size"`
+	UploadURL       string    `json:"upload_url"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// the direct upload can be confirmed for this duration after the expiration of its url
+const directUploadConfirmDelay = 1 * time.Hour
+
+// PendingKey on which a direct upload is put until its confirmation
+func PendingKey(fileID string) string {
+	return fileID + ".pending"
+}
+
+func directUploadKey(fileID string) string {
+	return fmt.Sprintf("direct_upload:%s", fileID)
+}
+
+// StartDirectUpload generates a new encrypted file id and pre-signs an url to upload it
+func StartDirectUpload(
+	ctx context.Context, redConn *redis.Client, repo FileStorageRepo,
+	boxID, identityID string, size int64, expiration time.Duration,
+) (DirectUpload, error) {
+	upload := DirectUpload{
+		BoxID:      boxID,
+		IdentityID: identityID,
+		Size:       size,
+		ExpiresAt:  time.Now().Add(expiration),
+	}
+	var err error
+	if upload.EncryptedFileID, err = uuid.NewString(); err != nil {
+		return upload, merr.From(err).Desc("generating file id")
+	}
+	if upload.UploadURL, err = repo.PresignUpload(ctx, upload.EncryptedFileID, size, expiration); err != nil {
+		return upload, merr.From(err).Desc("presigning upload")
+	}
+	value, err := json.Marshal(upload)
+	if err != nil {
+		return upload, merr.From(err).Desc("encoding direct upload")
+	}
+	if _, err := redConn.Set(directUploadKey(upload.EncryptedFileID), value, expiration+directUploadConfirmDelay).Result(); err != nil {
+		return upload, merr.From(err).Desc("storing direct upload")
+	}
+	return upload, nil
+}
+
+// GetDirectUpload ...
+func GetDirectUpload(ctx context.Context, redConn *redis.Client, fileID string) (DirectUpload, error) {
+	var upload DirectUpload
+	value, err := redConn.Get(directUploadKey(fileID)).Result()
+	if err == redis.Nil {
+		return upload, merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	if err != nil {
+		return upload, merr.From(err).Desc("getting direct upload")
+	}
+	if err := json.Unmarshal([]byte(value), &upload); err != nil {
+		return upload, merr.From(err).Desc("decoding direct upload")
+	}
+	return upload, nil
+}
+
+// ConfirmDirectUpload moves the encrypted file from its pending key, checks it has been stored
+// with the expected size then ends the direct upload. A stored file with an unexpected size is removed.
+// The size is checked once moved since the upload url can still be used on the pending key.
+func ConfirmDirectUpload(ctx context.Context, redConn *redis.Client, repo FileStorageRepo, upload DirectUpload) error {
+	if err := repo.ConfirmUpload(ctx, upload.EncryptedFileID); err != nil {
+		if merr.IsANotFound(err) {
+			return merr.Conflict().Add("encrypted_file", merr.DVRequired).Desc("the file has not been uploaded")
+		}
+		return merr.From(err).Desc("moving pending file")
+	}
+	size, err := repo.Size(ctx, upload.EncryptedFileID)
+	if err != nil {
+		return merr.From(err).Desc("getting stored size")
+	}
+	if size != upload.Size {
+		if err := repo.Delete(ctx, upload.EncryptedFileID); err != nil {
+			return merr.From(err).Desc("deleting invalid file")
+		}
+		return merr.Conflict().Add("encrypted_file", merr.DVInvalid).
+			Descf("stored size %d differs from the expected %d", size, upload.Size)
+	}
+	if _, err := redConn.Del(directUploadKey(upload.EncryptedFileID)).Result(); err != nil {
+		return merr.From(err).Desc("deleting direct upload")
+	}
+	return nil
+}
@@ -2,33 +2,99 @@ package files
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"io"
+	"time"
 
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/atomic"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/box/repositories/sqlboiler"
 )
 
 // EncryptedFile ...
+// Several encrypted files with the same content can share the same stored data:
+// the storage key identifies it and the data is removed only with the last file referencing it.
 type EncryptedFile struct {
 	ID   string
 	Size int64
+	// StorageKey is the id of the data on the storage, the file id if empty on creation
+	StorageKey string
+	// ContentHash is the hex sha256 of the data, only computed on deduplicated uploads
+	ContentHash null.String
+	CreatedAt   time.Time
+}
+
+func encryptedFileFromSQLBoiler(dbEncryptedFile *sqlboiler.EncryptedFile) EncryptedFile {
+	return EncryptedFile{
+		ID:          dbEncryptedFile.ID,
+		Size:        dbEncryptedFile.Size,
+		StorageKey:  dbEncryptedFile.StorageKey,
+		ContentHash: dbEncryptedFile.ContentHash,
+		CreatedAt:   dbEncryptedFile.CreatedAt,
+	}
 }
 
 // FileStorageRepo ...
 type FileStorageRepo interface {
 	Upload(context.Context, string, io.Reader) error
 	Download(context.Context, string) (io.Reader, error)
+	// DownloadRange reads length bytes of the file starting at offset
+	DownloadRange(ctx context.Context, fileID string, offset, length int64) (io.ReadCloser, error)
 	Delete(context.Context, string) error
+
+	// chunked uploads: a storage upload id is returned on start
+	// and must be given to the next calls
+	StartChunkedUpload(ctx context.Context, fileID string) (string, error)
+	UploadChunk(ctx context.Context, fileID, uploadID string, number int, data []byte) error
+	ListChunks(ctx context.Context, fileID, uploadID string) ([]int, error)
+	CompleteChunkedUpload(ctx context.Context, fileID, uploadID string) error
+	AbortChunkedUpload(ctx context.Context, fileID, uploadID string) error
+
+	// direct transfers: the storage is accessed by clients through short-lived urls
+	// uploaded files are put on a pending key until ConfirmUpload moves them to their key,
+	// so upload urls cannot overwrite confirmed files
+	PresignUpload(ctx context.Context, fileID string, size int64, expiration time.Duration) (string, error)
+	ConfirmUpload(ctx context.Context, fileID string) error
+	PresignDownload(ctx context.Context, fileID string, expiration time.Duration) (string, error)
+	Size(ctx context.Context, fileID string) (int64, error)
+
+	// List all stored files, used to check the consistency of the storage
+	List(ctx context.Context) ([]StoredFile, error)
+	// ListChunkedUploads not completed nor aborted, used to clean up abandoned upload sessions
+	ListChunkedUploads(ctx context.Context) ([]ChunkedUpload, error)
+}
+
+// StoredFile describes data found on the storage
+type StoredFile struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ChunkedUpload describes a chunked upload in progress on the storage
+type ChunkedUpload struct {
+	FileID       string
+	UploadID     string
+	LastModified time.Time
 }
 
 // Create ...
 func Create(ctx context.Context, exec boil.ContextExecutor, encryptedFile EncryptedFile) error {
+	if encryptedFile.StorageKey == "" {
+		encryptedFile.StorageKey = encryptedFile.ID
+	}
 	toStore := sqlboiler.EncryptedFile{
-		ID:   encryptedFile.ID,
-		Size: encryptedFile.Size,
+		ID:          encryptedFile.ID,
+		Size:        encryptedFile.Size,
+		StorageKey:  encryptedFile.StorageKey,
+		ContentHash: encryptedFile.ContentHash,
 	}
 	return toStore.Insert(ctx, exec, boil.Infer())
 }
@@ -43,12 +109,112 @@ func Get(ctx context.Context, exec boil.ContextExecutor, fileID string) (*Encryp
 		return nil, err
 	}
 
-	encryptedFile := EncryptedFile{
-		ID:   dbEncryptedFile.ID,
-		Size: dbEncryptedFile.Size,
+	encryptedFile := encryptedFileFromSQLBoiler(dbEncryptedFile)
+	return &encryptedFile, nil
+}
+
+// List all encrypted files
+func List(ctx context.Context, exec boil.ContextExecutor) ([]EncryptedFile, error) {
+	dbEncryptedFiles, err := sqlboiler.EncryptedFiles().All(ctx, exec)
+	if err != nil {
+		return nil, err
 	}
+	encryptedFiles := make([]EncryptedFile, len(dbEncryptedFiles))
+	for i, dbEncryptedFile := range dbEncryptedFiles {
+		encryptedFiles[i] = encryptedFileFromSQLBoiler(dbEncryptedFile)
+	}
+	return encryptedFiles, nil
+}
 
-	return &encryptedFile, nil
+// HashContent returns the hex sha256 of the data
+func HashContent(encData io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, encData); err != nil {
+		return "", merr.From(err).Desc("hashing content")
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CreateDeduplicated hashes the data then creates the encrypted file entity.
+// The entity references the stored data of a file with the same content if any,
+// otherwise the data is uploaded. It must be seekable since it is read twice.
+func CreateDeduplicated(ctx context.Context, exec boil.ContextExecutor, repo FileStorageRepo, encryptedFile *EncryptedFile, encData io.ReadSeeker) error {
+	hash, err := HashContent(encData)
+	if err != nil {
+		return err
+	}
+	encryptedFile.ContentHash = null.StringFrom(hash)
+
+	return inTransaction(ctx, exec, func(tr boil.ContextExecutor) error {
+		if err := createReferencingSameContent(ctx, tr, encryptedFile); err != nil {
+			return err
+		}
+		// the data is already stored
+		if encryptedFile.StorageKey != encryptedFile.ID {
+			return nil
+		}
+		// the upload is done in the transaction so no other file refers the data before it is stored
+		if _, err := encData.Seek(0, io.SeekStart); err != nil {
+			return merr.From(err).Desc("rewinding content")
+		}
+		return repo.Upload(ctx, encryptedFile.StorageKey, encData)
+	})
+}
+
+// DeduplicateStored creates the entity of an encrypted file whose data has already been stored under its id,
+// by chunked or direct uploads. The stored data is hashed then replaced by the one of a file
+// with the same content if any.
+func DeduplicateStored(ctx context.Context, exec boil.ContextExecutor, repo FileStorageRepo, encryptedFile *EncryptedFile) error {
+	encData, err := repo.Download(ctx, encryptedFile.ID)
+	if err != nil {
+		return merr.From(err).Desc("downloading stored data")
+	}
+	hash, err := HashContent(encData)
+	if closer, ok := encData.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	if err != nil {
+		return err
+	}
+	encryptedFile.ContentHash = null.StringFrom(hash)
+
+	if err := inTransaction(ctx, exec, func(tr boil.ContextExecutor) error {
+		return createReferencingSameContent(ctx, tr, encryptedFile)
+	}); err != nil {
+		return err
+	}
+	if encryptedFile.StorageKey == encryptedFile.ID {
+		return nil
+	}
+	// the duplicate is orphan stored data the storage check job would remove anyway
+	if err := repo.Delete(ctx, encryptedFile.ID); err != nil {
+		logger.FromCtx(ctx).Warn().Err(err).Msgf("could not delete duplicate stored data %s", encryptedFile.ID)
+	}
+	return nil
+}
+
+// createReferencingSameContent creates the encrypted file entity referencing the stored data
+// of a file with the same hash and size if any, its own id being the storage key otherwise.
+// The files with the same content are locked so their stored data cannot be removed meanwhile.
+func createReferencingSameContent(ctx context.Context, tr boil.ContextExecutor, encryptedFile *EncryptedFile) error {
+	encryptedFile.StorageKey = encryptedFile.ID
+
+	sameContent, err := sqlboiler.EncryptedFiles(
+		sqlboiler.EncryptedFileWhere.ContentHash.EQ(encryptedFile.ContentHash),
+		sqlboiler.EncryptedFileWhere.Size.EQ(encryptedFile.Size),
+		qm.For("UPDATE"),
+	).One(ctx, tr)
+	if err != nil && err != sql.ErrNoRows {
+		return merr.From(err).Desc("finding same content")
+	}
+	if err == nil {
+		encryptedFile.StorageKey = sameContent.StorageKey
+	}
+
+	if err := Create(ctx, tr, *encryptedFile); err != nil {
+		return merr.From(err).Desc("creating file")
+	}
+	return nil
 }
 
 // Upload ...
@@ -56,22 +222,75 @@ func Upload(ctx context.Context, repo FileStorageRepo, fileID string, encData io
 	return repo.Upload(ctx, fileID, encData)
 }
 
-// Download ...
-func Download(ctx context.Context, repo FileStorageRepo, fileID string) (io.Reader, error) {
-	return repo.Download(ctx, fileID)
+// Download the stored data of the encrypted file
+func Download(ctx context.Context, repo FileStorageRepo, encryptedFile EncryptedFile) (io.Reader, error) {
+	return repo.Download(ctx, encryptedFile.StorageKey)
 }
 
-// Delete ...
+// DownloadRange ...
+func DownloadRange(ctx context.Context, repo FileStorageRepo, encryptedFile EncryptedFile, offset, length int64) (io.ReadCloser, error) {
+	return repo.DownloadRange(ctx, encryptedFile.StorageKey, offset, length)
+}
+
+// Delete the file entity and decrements the references to its stored data,
+// the stored data being removed once no file references it anymore.
+// The references are locked so a deduplicated creation cannot refer the data being removed.
 func Delete(ctx context.Context, exec boil.ContextExecutor, repo FileStorageRepo, fileID string) error {
-	// delete the stored file
-	if err := repo.Delete(ctx, fileID); err != nil {
-		return err
+	return inTransaction(ctx, exec, func(tr boil.ContextExecutor) error {
+		return deleteLocked(ctx, tr, repo, fileID)
+	})
+}
+
+func deleteLocked(ctx context.Context, tr boil.ContextExecutor, repo FileStorageRepo, fileID string) error {
+	encryptedFile, err := Get(ctx, tr, fileID)
+	if err != nil {
+		// consider the stored file has the id of the entity if this one does not exist anymore
+		if !merr.IsANotFound(err) {
+			return err
+		}
+		encryptedFile = &EncryptedFile{ID: fileID, StorageKey: fileID}
+	}
+
+	references, err := sqlboiler.EncryptedFiles(
+		sqlboiler.EncryptedFileWhere.StorageKey.EQ(encryptedFile.StorageKey),
+		qm.For("UPDATE"),
+	).All(ctx, tr)
+	if err != nil {
+		return merr.From(err).Desc("locking references")
 	}
 
 	// delete file entity (ignoring the no row affected error)
-	if _, err := sqlboiler.EncryptedFiles(sqlboiler.EncryptedFileWhere.ID.EQ(fileID)).DeleteAll(ctx, exec); err != nil {
+	if _, err := sqlboiler.EncryptedFiles(sqlboiler.EncryptedFileWhere.ID.EQ(fileID)).DeleteAll(ctx, tr); err != nil {
 		return err
 	}
+	for _, reference := range references {
+		if reference.ID != fileID {
+			return nil
+		}
+	}
 
+	// delete the stored file
+	return repo.Delete(ctx, encryptedFile.StorageKey)
+}
+
+// inTransaction runs fn in a transaction begun on exec,
+// or directly on exec if this one is already a transaction
+func inTransaction(ctx context.Context, exec boil.ContextExecutor, fn func(boil.ContextExecutor) error) (err error) {
+	beginner, ok := exec.(boil.ContextBeginner)
+	if !ok {
+		return fn(exec)
+	}
+	tr, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return merr.From(err).Desc("initing transaction")
+	}
+	defer atomic.SQLRollback(ctx, tr, &err)
+
+	if err = fn(tr); err != nil {
+		return err
+	}
+	if err = tr.Commit(); err != nil {
+		return merr.From(err).Desc("committing transaction")
+	}
 	return nil
 }
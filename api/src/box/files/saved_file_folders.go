@@ -0,0 +1,138 @@
+package files
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/repositories/sqlboiler"
+)
+
+// SavedFileFolder organizes the saved files of an identity in a hierarchy.
+// Folders without parent are at the root of the vault.
+type SavedFileFolder struct {
+	ID            string      `json:"id"`
+	IdentityID    string      `json:"identity_id"`
+	ParentID      null.String `json:"parent_id"`
+	EncryptedName string      `json:"encrypted_name"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// CreateSavedFileFolder ...
+func CreateSavedFileFolder(ctx context.Context, exec boil.ContextExecutor, folder *SavedFileFolder) error {
+	toStore := sqlboiler.SavedFileFolder{
+		ID:            folder.ID,
+		IdentityID:    folder.IdentityID,
+		ParentID:      folder.ParentID,
+		EncryptedName: folder.EncryptedName,
+	}
+	if err := toStore.Insert(ctx, exec, boil.Infer()); err != nil {
+		return err
+	}
+	folder.CreatedAt = toStore.CreatedAt
+	return nil
+}
+
+// GetSavedFileFolder ...
+func GetSavedFileFolder(ctx context.Context, exec boil.ContextExecutor, id string) (SavedFileFolder, error) {
+	dbFolder, err := sqlboiler.FindSavedFileFolder(ctx, exec, id)
+	if err == sql.ErrNoRows {
+		return SavedFileFolder{}, merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	if err != nil {
+		return SavedFileFolder{}, err
+	}
+	return folderFromSQLBoiler(dbFolder), nil
+}
+
+// ListSavedFileFolders returns all the folders of the identity, the client builds the hierarchy
+func ListSavedFileFolders(ctx context.Context, exec boil.ContextExecutor, identityID string) ([]SavedFileFolder, error) {
+	dbFolders, err := sqlboiler.SavedFileFolders(
+		sqlboiler.SavedFileFolderWhere.IdentityID.EQ(identityID),
+		qm.OrderBy(sqlboiler.SavedFileFolderColumns.CreatedAt),
+	).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	folders := make([]SavedFileFolder, len(dbFolders))
+	for idx, dbFolder := range dbFolders {
+		folders[idx] = folderFromSQLBoiler(dbFolder)
+	}
+	return folders, nil
+}
+
+// UpdateSavedFileFolder updates the parent and the encrypted name of the folder
+func UpdateSavedFileFolder(ctx context.Context, exec boil.ContextExecutor, folder SavedFileFolder) error {
+	toUpdate := sqlboiler.SavedFileFolder{
+		ID:            folder.ID,
+		ParentID:      folder.ParentID,
+		EncryptedName: folder.EncryptedName,
+	}
+	rowAff, err := toUpdate.Update(ctx, exec, boil.Whitelist(
+		sqlboiler.SavedFileFolderColumns.ParentID,
+		sqlboiler.SavedFileFolderColumns.EncryptedName,
+	))
+	if err != nil {
+		return err
+	}
+	if rowAff == 0 {
+		return merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	return nil
+}
+
+// DeleteSavedFileFolder removes the folder which must be empty
+func DeleteSavedFileFolder(ctx context.Context, exec boil.ContextExecutor, id string) error {
+	subFolders, err := sqlboiler.SavedFileFolders(sqlboiler.SavedFileFolderWhere.ParentID.EQ(null.StringFrom(id))).Count(ctx, exec)
+	if err != nil {
+		return merr.From(err).Desc("counting sub folders")
+	}
+	savedFiles, err := sqlboiler.SavedFiles(sqlboiler.SavedFileWhere.FolderID.EQ(null.StringFrom(id))).Count(ctx, exec)
+	if err != nil {
+		return merr.From(err).Desc("counting saved files")
+	}
+	if subFolders != 0 || savedFiles != 0 {
+		return merr.Conflict().Add("id", merr.DVConflict).Desc("the folder is not empty")
+	}
+
+	rowAff, err := sqlboiler.SavedFileFolders(sqlboiler.SavedFileFolderWhere.ID.EQ(id)).DeleteAll(ctx, exec)
+	if err != nil {
+		return err
+	}
+	if rowAff == 0 {
+		return merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	return nil
+}
+
+// MustNotBeDescendant checks the folder is neither the ancestor nor the folder itself:
+// moving the ancestor into it would create a cycle.
+func MustNotBeDescendant(ctx context.Context, exec boil.ContextExecutor, folderID, ancestorID string) error {
+	currentID := folderID
+	for currentID != "" {
+		if currentID == ancestorID {
+			return merr.Conflict().Add("parent_id", merr.DVConflict).Desc("a folder cannot be moved into itself or its sub folders")
+		}
+		folder, err := GetSavedFileFolder(ctx, exec, currentID)
+		if err != nil {
+			return merr.From(err).Desc("getting parent folder")
+		}
+		currentID = folder.ParentID.String
+	}
+	return nil
+}
+
+func folderFromSQLBoiler(dbFolder *sqlboiler.SavedFileFolder) SavedFileFolder {
+	return SavedFileFolder{
+		ID:            dbFolder.ID,
+		IdentityID:    dbFolder.IdentityID,
+		ParentID:      dbFolder.ParentID,
+		EncryptedName: dbFolder.EncryptedName,
+		CreatedAt:     dbFolder.CreatedAt,
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/box/repositories/sqlboiler"
@@ -14,12 +16,15 @@ import (
 
 // SavedFile ...
 type SavedFile struct {
-	ID                string    `json:"id"`
-	IdentityID        string    `json:"identity_id"`
-	EncryptedFileID   string    `json:"encrypted_file_id"`
-	EncryptedMetadata string    `json:"encrypted_metadata"`
-	KeyFingerprint    string    `json:"key_fingerprint"`
-	CreatedAt         time.Time `json:"created_at"`
+	ID                string      `json:"id"`
+	IdentityID        string      `json:"identity_id"`
+	EncryptedFileID   string      `json:"encrypted_file_id"`
+	EncryptedMetadata string      `json:"encrypted_metadata"`
+	KeyFingerprint    string      `json:"key_fingerprint"`
+	CreatedAt         time.Time   `json:"created_at"`
+	FolderID          null.String `json:"folder_id"`
+	// Tags are blind indexes computed by the client, the server only compares them
+	Tags []string `json:"tags"`
 }
 
 // SavedFileFilters ...
@@ -27,10 +32,27 @@ type SavedFileFilters struct {
 	FileID           string
 	IdentityID       string
 	EncryptedFileIDs []string
-	Offset           *int
-	Limit            *int
+	// FolderID restricts to files directly in the folder, RootOnly to files not in any folder
+	FolderID string
+	RootOnly bool
+	// Tags restricts to files having all the tags
+	Tags []string
+	// SortBy is one of the SavedFileSort* values, created_at is used by default
+	SortBy    string
+	SortOrder string
+	Offset    *int
+	Limit     *int
 }
 
+// the sortable saved files properties and orders
+const (
+	SavedFileSortCreatedAt = "created_at"
+	SavedFileSortSize      = "size"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
 // CreateSavedFile ...
 func CreateSavedFile(ctx context.Context, exec boil.ContextExecutor, savedFile *SavedFile) error {
 	toStore := sqlboiler.SavedFile{
@@ -39,6 +61,11 @@ func CreateSavedFile(ctx context.Context, exec boil.ContextExecutor, savedFile *
 		EncryptedFileID:   savedFile.EncryptedFileID,
 		EncryptedMetadata: savedFile.EncryptedMetadata,
 		KeyFingerprint:    savedFile.KeyFingerprint,
+		FolderID:          savedFile.FolderID,
+		Tags:              savedFile.Tags,
+	}
+	if toStore.Tags == nil {
+		toStore.Tags = types.StringArray{}
 	}
 	if err := toStore.Insert(ctx, exec, boil.Infer()); err != nil {
 		return err
@@ -47,6 +74,31 @@ func CreateSavedFile(ctx context.Context, exec boil.ContextExecutor, savedFile *
 	return nil
 }
 
+// UpdateSavedFile updates the folder, the encrypted metadata and the tags of the saved file
+func UpdateSavedFile(ctx context.Context, exec boil.ContextExecutor, savedFile *SavedFile) error {
+	toUpdate := sqlboiler.SavedFile{
+		ID:                savedFile.ID,
+		EncryptedMetadata: savedFile.EncryptedMetadata,
+		FolderID:          savedFile.FolderID,
+		Tags:              savedFile.Tags,
+	}
+	if toUpdate.Tags == nil {
+		toUpdate.Tags = types.StringArray{}
+	}
+	rowAff, err := toUpdate.Update(ctx, exec, boil.Whitelist(
+		sqlboiler.SavedFileColumns.EncryptedMetadata,
+		sqlboiler.SavedFileColumns.FolderID,
+		sqlboiler.SavedFileColumns.Tags,
+	))
+	if err != nil {
+		return err
+	}
+	if rowAff == 0 {
+		return merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	return nil
+}
+
 // DeleteSavedFile ...
 func DeleteSavedFile(ctx context.Context, exec boil.ContextExecutor, id string) error {
 	rowAff, err := sqlboiler.SavedFiles(sqlboiler.SavedFileWhere.ID.EQ(id)).DeleteAll(ctx, exec)
@@ -61,10 +113,12 @@ func DeleteSavedFile(ctx context.Context, exec boil.ContextExecutor, id string)
 
 // CountSavedFilesByIdentityID ...
 func CountSavedFilesByIdentityID(ctx context.Context, exec boil.ContextExecutor, identityID string) (int, error) {
-	mods := []qm.QueryMod{
-		sqlboiler.SavedFileWhere.IdentityID.EQ(identityID),
-	}
-	count, err := sqlboiler.SavedFiles(mods...).Count(ctx, exec)
+	return CountSavedFiles(ctx, exec, SavedFileFilters{IdentityID: identityID})
+}
+
+// CountSavedFiles matching the filters, ignoring pagination and sorting
+func CountSavedFiles(ctx context.Context, exec boil.ContextExecutor, filters SavedFileFilters) (int, error) {
+	count, err := sqlboiler.SavedFiles(savedFileFilterMods(filters)...).Count(ctx, exec)
 	if err != nil {
 		return 0, merr.From(err).Desc("count save files db")
 	}
@@ -74,18 +128,21 @@ func CountSavedFilesByIdentityID(ctx context.Context, exec boil.ContextExecutor,
 
 // ListSavedFiles ...
 func ListSavedFiles(ctx context.Context, exec boil.ContextExecutor, filters SavedFileFilters) ([]SavedFile, error) {
-	mods := []qm.QueryMod{
-		qm.OrderBy(sqlboiler.SavedFileColumns.CreatedAt + " DESC"),
-	}
+	mods := savedFileFilterMods(filters)
 
-	// set identity filter
-	if filters.IdentityID != "" {
-		mods = append(mods, sqlboiler.SavedFileWhere.IdentityID.EQ(filters.IdentityID))
+	// set sorting - the id makes the order stable for pagination
+	order := "DESC"
+	if filters.SortOrder == SortOrderAsc {
+		order = "ASC"
 	}
-
-	// set ids filter
-	if len(filters.EncryptedFileIDs) != 0 {
-		mods = append(mods, sqlboiler.SavedFileWhere.EncryptedFileID.IN(filters.EncryptedFileIDs))
+	if filters.SortBy == SavedFileSortSize {
+		mods = append(mods,
+			qm.Select(`"saved_file".*`),
+			qm.InnerJoin(`encrypted_file ON "encrypted_file"."id" = "saved_file"."encrypted_file_id"`),
+			qm.OrderBy(`"encrypted_file"."size" `+order+`, "saved_file"."id"`),
+		)
+	} else {
+		mods = append(mods, qm.OrderBy(`"saved_file"."created_at" `+order+`, "saved_file"."id"`))
 	}
 
 	// add offset for pagination
@@ -114,6 +171,33 @@ func ListSavedFiles(ctx context.Context, exec boil.ContextExecutor, filters Save
 	return savedFiles, nil
 }
 
+func savedFileFilterMods(filters SavedFileFilters) []qm.QueryMod {
+	mods := []qm.QueryMod{}
+
+	// set identity filter
+	if filters.IdentityID != "" {
+		mods = append(mods, sqlboiler.SavedFileWhere.IdentityID.EQ(filters.IdentityID))
+	}
+
+	// set ids filter
+	if len(filters.EncryptedFileIDs) != 0 {
+		mods = append(mods, sqlboiler.SavedFileWhere.EncryptedFileID.IN(filters.EncryptedFileIDs))
+	}
+
+	// set folder filter
+	if filters.FolderID != "" {
+		mods = append(mods, sqlboiler.SavedFileWhere.FolderID.EQ(null.StringFrom(filters.FolderID)))
+	} else if filters.RootOnly {
+		mods = append(mods, sqlboiler.SavedFileWhere.FolderID.IsNull())
+	}
+
+	// set tags filter: the file tags must contain all the requested ones
+	if len(filters.Tags) != 0 {
+		mods = append(mods, qm.Where(`"saved_file"."tags" @> ?`, types.StringArray(filters.Tags)))
+	}
+	return mods
+}
+
 // GetSavedFile ...
 func GetSavedFile(ctx context.Context, exec boil.ContextExecutor, id string) (*SavedFile, error) {
 	dbSavedFile, err := sqlboiler.SavedFiles(sqlboiler.SavedFileWhere.ID.EQ(id)).One(ctx, exec)
@@ -130,6 +214,10 @@ func GetSavedFile(ctx context.Context, exec boil.ContextExecutor, id string) (*S
 }
 
 func toDomain(dbSavedFile *sqlboiler.SavedFile) SavedFile {
+	tags := []string(dbSavedFile.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
 	return SavedFile{
 		ID:                dbSavedFile.ID,
 		IdentityID:        dbSavedFile.IdentityID,
@@ -137,5 +225,7 @@ func toDomain(dbSavedFile *sqlboiler.SavedFile) SavedFile {
 		EncryptedMetadata: dbSavedFile.EncryptedMetadata,
 		KeyFingerprint:    dbSavedFile.KeyFingerprint,
 		CreatedAt:         dbSavedFile.CreatedAt,
+		FolderID:          dbSavedFile.FolderID,
+		Tags:              tags,
 	}
 }
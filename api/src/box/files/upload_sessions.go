@@ -0,0 +1,165 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/uuid"
+)
+
+// ChunkSize is the size of all chunks of an upload session except the last one
+// it must be greater than 5MB which is the minimum part size of s3 multipart uploads
+const ChunkSize int64 = 8 * 1024 * 1024
+
+// UploadSessionTTL is the duration an upload session lives after its last chunk upload
+const UploadSessionTTL = 24 * time.Hour
+
+// UploadSession is a resumable upload of an encrypted file in numbered chunks
+type UploadSession struct {
+	ID              string    `json:"id"`
+	BoxID           string    `json:"box_id"`
+	IdentityID      string    `json:"identity_id"`
+	EncryptedFileID string    `json:"encrypted_file_id"`
+	StorageUploadID string    `json:"storage_upload_id"`
+	Size            int64     `json:"size"`
+	ChunkSize       int64     `json:"chunk_size"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// ChunkCount returns the number of chunks expected to complete the session
+func (s UploadSession) ChunkCount() int {
+	return int((s.Size + s.ChunkSize - 1) / s.ChunkSize)
+}
+
+// expectedChunkSize returns the size the chunk must have, 0 if its number is out of range
+func (s UploadSession) expectedChunkSize(number int) int64 {
+	count := s.ChunkCount()
+	if number < 1 || number > count {
+		return 0
+	}
+	if number < count {
+		return s.ChunkSize
+	}
+	return s.Size - int64(count-1)*s.ChunkSize
+}
+
+func uploadSessionKey(id string) string {
+	return fmt.Sprintf("upload_session:%s", id)
+}
+
+// StartUploadSession for a new encrypted file of the given size
+func StartUploadSession(
+	ctx context.Context, redConn *redis.Client, repo FileStorageRepo,
+	boxID, identityID string, size int64,
+) (UploadSession, error) {
+	session := UploadSession{
+		BoxID:      boxID,
+		IdentityID: identityID,
+		Size:       size,
+		ChunkSize:  ChunkSize,
+	}
+	var err error
+	if session.ID, err = uuid.NewString(); err != nil {
+		return session, merr.From(err).Desc("generating session id")
+	}
+	if session.EncryptedFileID, err = uuid.NewString(); err != nil {
+		return session, merr.From(err).Desc("generating file id")
+	}
+	if session.StorageUploadID, err = repo.StartChunkedUpload(ctx, session.EncryptedFileID); err != nil {
+		return session, merr.From(err).Desc("starting storage upload")
+	}
+	if err := storeUploadSession(redConn, &session); err != nil {
+		return session, merr.From(err).Desc("storing session")
+	}
+	return session, nil
+}
+
+// GetUploadSession ...
+func GetUploadSession(ctx context.Context, redConn *redis.Client, id string) (UploadSession, error) {
+	var session UploadSession
+	value, err := redConn.Get(uploadSessionKey(id)).Result()
+	if err == redis.Nil {
+		return session, merr.NotFound().Add("id", merr.DVNotFound)
+	}
+	if err != nil {
+		return session, merr.From(err).Desc("getting session")
+	}
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return session, merr.From(err).Desc("decoding session")
+	}
+	return session, nil
+}
+
+// UploadSessionChunk stores the numbered chunk and extends the session lifetime.
+// Uploading again a chunk with the same number overrides it.
+func UploadSessionChunk(
+	ctx context.Context, redConn *redis.Client, repo FileStorageRepo,
+	session *UploadSession, number int, data []byte,
+) error {
+	expectedSize := session.expectedChunkSize(number)
+	if expectedSize == 0 {
+		return merr.BadRequest().Ori(merr.OriPath).Add("number", merr.DVInvalid).
+			Descf("chunk number must be between 1 and %d", session.ChunkCount())
+	}
+	if int64(len(data)) != expectedSize {
+		return merr.BadRequest().Ori(merr.OriBody).Add("chunk", merr.DVInvalid).
+			Descf("chunk %d must be %d bytes long", number, expectedSize)
+	}
+	if err := repo.UploadChunk(ctx, session.EncryptedFileID, session.StorageUploadID, number, data); err != nil {
+		return merr.From(err).Desc("uploading chunk")
+	}
+	if err := storeUploadSession(redConn, session); err != nil {
+		return merr.From(err).Desc("refreshing session")
+	}
+	return nil
+}
+
+// ListUploadSessionChunks returns the sorted numbers of already uploaded chunks
+func ListUploadSessionChunks(ctx context.Context, repo FileStorageRepo, session UploadSession) ([]int, error) {
+	return repo.ListChunks(ctx, session.EncryptedFileID, session.StorageUploadID)
+}
+
+// CompleteUploadSession assembles the chunks into the encrypted file and ends the session.
+// All chunks must have been uploaded.
+func CompleteUploadSession(ctx context.Context, redConn *redis.Client, repo FileStorageRepo, session UploadSession) error {
+	numbers, err := ListUploadSessionChunks(ctx, repo, session)
+	if err != nil {
+		return merr.From(err).Desc("listing chunks")
+	}
+	if len(numbers) != session.ChunkCount() {
+		return merr.Conflict().Add("chunks", merr.DVRequired).
+			Descf("%d chunks received out of %d", len(numbers), session.ChunkCount())
+	}
+	if err := repo.CompleteChunkedUpload(ctx, session.EncryptedFileID, session.StorageUploadID); err != nil {
+		return merr.From(err).Desc("completing storage upload")
+	}
+	if _, err := redConn.Del(uploadSessionKey(session.ID)).Result(); err != nil {
+		return merr.From(err).Desc("deleting session")
+	}
+	return nil
+}
+
+// AbortUploadSession removes uploaded chunks and ends the session
+func AbortUploadSession(ctx context.Context, redConn *redis.Client, repo FileStorageRepo, session UploadSession) error {
+	if err := repo.AbortChunkedUpload(ctx, session.EncryptedFileID, session.StorageUploadID); err != nil {
+		return merr.From(err).Desc("aborting storage upload")
+	}
+	if _, err := redConn.Del(uploadSessionKey(session.ID)).Result(); err != nil {
+		return merr.From(err).Desc("deleting session")
+	}
+	return nil
+}
+
+func storeUploadSession(redConn *redis.Client, session *UploadSession) error {
+	session.ExpiresAt = time.Now().Add(UploadSessionTTL)
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	_, err = redConn.Set(uploadSessionKey(session.ID), value, UploadSessionTTL).Result()
+	return err
+}
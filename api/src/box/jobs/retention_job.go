@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/atomic"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/external"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// RetentionJob contains connectors for the retention job
+type RetentionJob struct {
+	boxDB     *sql.DB
+	redConn   *redis.Client
+	filesRepo files.FileStorageRepo
+
+	identityRepo external.IdentityRepo
+}
+
+// NewRetentionJob constructor
+func NewRetentionJob(
+	boxDB *sql.DB,
+	redConn *redis.Client,
+	filesRepo files.FileStorageRepo,
+	identityRepo external.IdentityRepo,
+) *RetentionJob {
+	return &RetentionJob{
+		boxDB:        boxDB,
+		redConn:      redConn,
+		filesRepo:    filesRepo,
+		identityRepo: identityRepo,
+	}
+}
+
+// ExpireMessages deletes all messages that have expired, considering their time-to-live
+// and the retention policy of their box.
+// A failure on one message is logged and does not stop the job.
+func (job *RetentionJob) ExpireMessages(ctx context.Context) error {
+	messageIDs, err := events.ListExpiredMessageIDs(ctx, job.boxDB, time.Now())
+	if err != nil {
+		return merr.From(err).Desc("listing expired messages")
+	}
+
+	identityMapper := events.NewIdentityMapper(job.identityRepo)
+	expiredCount := 0
+	for _, messageID := range messageIDs {
+		if err := job.expire(ctx, identityMapper, messageID); err != nil {
+			logger.FromCtx(ctx).Error().Err(err).Msgf("could not expire message %s", messageID)
+			continue
+		}
+		expiredCount++
+	}
+	logger.FromCtx(ctx).Info().Msgf("%d/%d expired messages deleted", expiredCount, len(messageIDs))
+	return nil
+}
+
+func (job *RetentionJob) expire(ctx context.Context, identityMapper *events.IdentityMapper, messageID string) (err error) {
+	// one transaction per message so a failure does not roll back the whole job
+	tr, err := job.boxDB.BeginTx(ctx, nil)
+	if err != nil {
+		return merr.From(err).Desc("initing transaction")
+	}
+	defer atomic.SQLRollback(ctx, tr, &err)
+
+	e, msg, err := events.ExpireMessage(ctx, tr, job.filesRepo, messageID)
+	if err != nil {
+		return err
+	}
+	if err = tr.Commit(); err != nil {
+		return merr.From(err).Desc("committing transaction")
+	}
+
+	// after handlers update the used space of the box and alert members
+	for _, after := range events.Handler(e.Type).After {
+		if err := after(ctx, &e, job.boxDB, job.redConn, identityMapper, job.filesRepo, msg); err != nil {
+			// we log the error but we don’t return it
+			logger.FromCtx(ctx).Warn().Err(err).Msgf("after %s event", e.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/quota"
+)
+
+// StorageCheckJob contains connectors for the storage check job
+type StorageCheckJob struct {
+	boxDB     *sql.DB
+	filesRepo files.FileStorageRepo
+
+	// files more recent than this period are ignored since they might be uploading
+	gracePeriod time.Duration
+	// inconsistencies are repaired if true, only reported otherwise
+	repair bool
+}
+
+// NewStorageCheckJob constructor
+func NewStorageCheckJob(
+	boxDB *sql.DB,
+	filesRepo files.FileStorageRepo,
+	gracePeriod time.Duration,
+	repair bool,
+) *StorageCheckJob {
+	return &StorageCheckJob{
+		boxDB:       boxDB,
+		filesRepo:   filesRepo,
+		gracePeriod: gracePeriod,
+		repair:      repair,
+	}
+}
+
+// StorageReport lists the inconsistencies found between the storage and the database
+type StorageReport struct {
+	// stored data no encrypted file refers
+	OrphanStoredData []string `json:"orphan_stored_data"`
+	// encrypted files whose data is not stored
+	MissingStoredData []string `json:"missing_stored_data"`
+	// encrypted files whose stored data has another size
+	SizeMismatches []string `json:"size_mismatches"`
+	// encrypted files no saved file nor message refers
+	OrphanFiles []string `json:"orphan_files"`
+	// boxes whose used space differs from the sum of their messages sizes
+	WrongBoxUsedSpaces []string `json:"wrong_box_used_spaces"`
+	// chunked uploads of expired upload sessions
+	AbandonedUploads []string `json:"abandoned_uploads"`
+}
+
+// Check walks the storage and the database to report inconsistencies, repairing them if asked:
+// - orphan stored data is deleted
+// - encrypted files with missing data are deleted with the saved files referring them
+// - orphan encrypted files are deleted, decrementing the references of their stored data
+// - box used spaces are recomputed
+// - chunked uploads of expired upload sessions are aborted
+// Size mismatches are only reported.
+// A failure on one repair is logged and does not stop the job.
+func (job *StorageCheckJob) Check(ctx context.Context) (StorageReport, error) {
+	var report StorageReport
+	before := time.Now().Add(-job.gracePeriod)
+
+	stored, err := job.filesRepo.List(ctx)
+	if err != nil {
+		return report, merr.From(err).Desc("listing stored files")
+	}
+	storedByKey := make(map[string]files.StoredFile, len(stored))
+	for _, storedFile := range stored {
+		storedByKey[storedFile.Key] = storedFile
+	}
+
+	encryptedFiles, err := files.List(ctx, job.boxDB)
+	if err != nil {
+		return report, merr.From(err).Desc("listing encrypted files")
+	}
+	referencedKeys := make(map[string]bool, len(encryptedFiles))
+	for _, encryptedFile := range encryptedFiles {
+		referencedKeys[encryptedFile.StorageKey] = true
+	}
+
+	// 1. stored data without encrypted file
+	for _, storedFile := range stored {
+		if referencedKeys[storedFile.Key] || storedFile.LastModified.After(before) {
+			continue
+		}
+		report.OrphanStoredData = append(report.OrphanStoredData, storedFile.Key)
+		if job.repair {
+			if err := job.filesRepo.Delete(ctx, storedFile.Key); err != nil {
+				logger.FromCtx(ctx).Error().Err(err).Msgf("could not delete orphan stored data %s", storedFile.Key)
+			}
+		}
+	}
+
+	// 2. encrypted files without stored data, with a wrong size or without referrer
+	for _, encryptedFile := range encryptedFiles {
+		if encryptedFile.CreatedAt.After(before) {
+			continue
+		}
+		storedFile, ok := storedByKey[encryptedFile.StorageKey]
+		if !ok {
+			report.MissingStoredData = append(report.MissingStoredData, encryptedFile.ID)
+			if job.repair {
+				if err := job.deleteFileWithoutData(ctx, encryptedFile); err != nil {
+					logger.FromCtx(ctx).Error().Err(err).Msgf("could not delete file %s without data", encryptedFile.ID)
+				}
+			}
+			continue
+		}
+		if storedFile.Size != encryptedFile.Size {
+			report.SizeMismatches = append(report.SizeMismatches, encryptedFile.ID)
+		}
+
+		isOrphan, err := events.IsFileOrphan(ctx, job.boxDB, encryptedFile.ID)
+		if err != nil {
+			return report, merr.From(err).Descf("checking file %s is orphan", encryptedFile.ID)
+		}
+		if !isOrphan {
+			continue
+		}
+		report.OrphanFiles = append(report.OrphanFiles, encryptedFile.ID)
+		if job.repair {
+			if err := files.Delete(ctx, job.boxDB, job.filesRepo, encryptedFile.ID); err != nil {
+				logger.FromCtx(ctx).Error().Err(err).Msgf("could not delete orphan file %s", encryptedFile.ID)
+			}
+		}
+	}
+
+	// 3. box used spaces
+	if err := job.checkBoxUsedSpaces(ctx, &report); err != nil {
+		return report, err
+	}
+
+	// 4. chunked uploads whose session has expired
+	if err := job.checkChunkedUploads(ctx, &report); err != nil {
+		return report, err
+	}
+
+	logger.FromCtx(ctx).Info().
+		Int("orphan_stored_data", len(report.OrphanStoredData)).
+		Int("missing_stored_data", len(report.MissingStoredData)).
+		Int("size_mismatches", len(report.SizeMismatches)).
+		Int("orphan_files", len(report.OrphanFiles)).
+		Int("wrong_box_used_spaces", len(report.WrongBoxUsedSpaces)).
+		Int("abandoned_uploads", len(report.AbandonedUploads)).
+		Bool("repaired", job.repair).
+		Msgf("storage checked: %d stored files, %d encrypted files", len(stored), len(encryptedFiles))
+	return report, nil
+}
+
+func (job *StorageCheckJob) deleteFileWithoutData(ctx context.Context, encryptedFile files.EncryptedFile) error {
+	savedFiles, err := files.ListSavedFiles(ctx, job.boxDB, files.SavedFileFilters{
+		EncryptedFileIDs: []string{encryptedFile.ID},
+	})
+	if err != nil {
+		return merr.From(err).Desc("listing saved files")
+	}
+	for _, savedFile := range savedFiles {
+		if err := files.DeleteSavedFile(ctx, job.boxDB, savedFile.ID); err != nil {
+			return merr.From(err).Descf("deleting saved file %s", savedFile.ID)
+		}
+	}
+	// the stored data being already missing, not found errors are expected
+	if err := files.Delete(ctx, job.boxDB, job.filesRepo, encryptedFile.ID); err != nil && !merr.IsANotFound(err) {
+		return merr.From(err).Desc("deleting file")
+	}
+	return nil
+}
+
+func (job *StorageCheckJob) checkBoxUsedSpaces(ctx context.Context, report *StorageReport) error {
+	boxIDs, err := events.ListBoxIDs(ctx, job.boxDB)
+	if err != nil {
+		return merr.From(err).Desc("listing boxes")
+	}
+	usedSpaces, err := quota.ListBoxUsedSpaces(ctx, job.boxDB, boxIDs)
+	if err != nil {
+		return merr.From(err).Desc("listing box used spaces")
+	}
+	currentValues := make(map[string]int64, len(usedSpaces))
+	for _, usedSpace := range usedSpaces {
+		currentValues[usedSpace.BoxID] = usedSpace.Value
+	}
+
+	for _, boxID := range boxIDs {
+		value, err := events.ComputeBoxUsedSpace(ctx, job.boxDB, boxID)
+		if err != nil {
+			return merr.From(err).Descf("computing used space of box %s", boxID)
+		}
+		if value == currentValues[boxID] {
+			continue
+		}
+		report.WrongBoxUsedSpaces = append(report.WrongBoxUsedSpaces, boxID)
+		if job.repair {
+			if err := quota.SetBoxUsedSpace(ctx, job.boxDB, boxID, value); err != nil {
+				logger.FromCtx(ctx).Error().Err(err).Msgf("could not set used space of box %s", boxID)
+			}
+		}
+	}
+	return nil
+}
+
+func (job *StorageCheckJob) checkChunkedUploads(ctx context.Context, report *StorageReport) error {
+	uploads, err := job.filesRepo.ListChunkedUploads(ctx)
+	if err != nil {
+		return merr.From(err).Desc("listing chunked uploads")
+	}
+	// a session lives at least until its ttl after its last chunk upload
+	period := job.gracePeriod
+	if period < files.UploadSessionTTL {
+		period = files.UploadSessionTTL
+	}
+	before := time.Now().Add(-period)
+
+	for _, upload := range uploads {
+		if upload.LastModified.After(before) {
+			continue
+		}
+		report.AbandonedUploads = append(report.AbandonedUploads, upload.FileID)
+		if job.repair {
+			if err := job.filesRepo.AbortChunkedUpload(ctx, upload.FileID, upload.UploadID); err != nil && !merr.IsANotFound(err) {
+				logger.FromCtx(ctx).Error().Err(err).Msgf("could not abort chunked upload of %s", upload.FileID)
+			}
+		}
+	}
+	return nil
+}
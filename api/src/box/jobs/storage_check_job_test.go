@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/files"
+)
+
+// uploadsRepo only implements the chunked uploads methods of the files.FileStorageRepo
+type uploadsRepo struct {
+	files.FileStorageRepo
+	uploads []files.ChunkedUpload
+	aborted []string
+}
+
+func (r *uploadsRepo) ListChunkedUploads(_ context.Context) ([]files.ChunkedUpload, error) {
+	return r.uploads, nil
+}
+
+func (r *uploadsRepo) AbortChunkedUpload(_ context.Context, fileID, uploadID string) error {
+	r.aborted = append(r.aborted, fileID+"/"+uploadID)
+	return nil
+}
+
+func TestCheckChunkedUploads(t *testing.T) {
+	now := time.Now()
+	newRepo := func() *uploadsRepo {
+		return &uploadsRepo{uploads: []files.ChunkedUpload{
+			{FileID: "abandoned", UploadID: "upload-1", LastModified: now.Add(-72 * time.Hour)},
+			{FileID: "in-progress", UploadID: "upload-2", LastModified: now.Add(-time.Hour)},
+			// older than the grace period but the session might still be alive
+			{FileID: "within-ttl", UploadID: "upload-3", LastModified: now.Add(-12 * time.Hour)},
+		}}
+	}
+
+	t.Run("report only", func(t *testing.T) {
+		repo := newRepo()
+		job := NewStorageCheckJob(nil, repo, 6*time.Hour, false)
+		var report StorageReport
+		assert.NoError(t, job.checkChunkedUploads(context.Background(), &report))
+		assert.Equal(t, []string{"abandoned"}, report.AbandonedUploads)
+		assert.Empty(t, repo.aborted)
+	})
+	t.Run("repair", func(t *testing.T) {
+		repo := newRepo()
+		job := NewStorageCheckJob(nil, repo, 6*time.Hour, true)
+		var report StorageReport
+		assert.NoError(t, job.checkChunkedUploads(context.Background(), &report))
+		assert.Equal(t, []string{"abandoned"}, report.AbandonedUploads)
+		assert.Equal(t, []string{"abandoned/upload-1"}, repo.aborted)
+	})
+}
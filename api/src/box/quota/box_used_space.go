@@ -85,3 +85,11 @@ func UpdateBoxUsedSpace(ctx context.Context, exec boil.ContextExecutor, BoxID st
 	_, err = currentBoxUsedSpace.Update(ctx, exec, boil.Infer())
 	return err
 }
+
+// SetBoxUsedSpace to the given value whatever the current one
+func SetBoxUsedSpace(ctx context.Context, exec boil.ContextExecutor, boxID string, value int64) error {
+	if err := DeleteBoxUsedSpace(ctx, exec, boxID); err != nil {
+		return err
+	}
+	return UpdateBoxUsedSpace(ctx, exec, boxID, value, 0)
+}
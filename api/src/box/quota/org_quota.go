@@ -0,0 +1,68 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/repositories/sqlboiler"
+)
+
+// an organization is given a base quotum until the instance grants it some quota,
+// so owning boxes through an organization does not bypass the identity quota
+const OrgBaseValue = BaseValue
+
+// OrgQuotum model
+// the storage quota of an organization limits the used space of the boxes it owns
+type OrgQuotum struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	OrgID     string    `json:"org_id"`
+	Value     int64     `json:"value"`
+	Origin    string    `json:"origin"`
+}
+
+func orgQuotumToDomain(dbQuotum sqlboiler.OrgStorageQuotum) OrgQuotum {
+	return OrgQuotum{
+		ID:        dbQuotum.ID,
+		CreatedAt: dbQuotum.CreatedAt,
+		OrgID:     dbQuotum.OrgID,
+		Value:     dbQuotum.Value,
+		Origin:    dbQuotum.Origin,
+	}
+}
+
+// ListOrg quota for a given orgID
+func ListOrg(ctx context.Context, exec boil.ContextExecutor, orgID string) ([]OrgQuotum, error) {
+	dbQuota, err := sqlboiler.OrgStorageQuota(sqlboiler.OrgStorageQuotumWhere.OrgID.EQ(orgID)).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	quota := make([]OrgQuotum, len(dbQuota))
+	for idx, quotum := range dbQuota {
+		quota[idx] = orgQuotumToDomain(*quotum)
+	}
+	return quota, nil
+}
+
+// TotalOrg storage quota of an organization by summing all its quota
+// the base quotum is counted if the organization has not been granted any quotum
+func TotalOrg(ctx context.Context, exec boil.ContextExecutor, orgID string) (int64, error) {
+	quota, err := ListOrg(ctx, exec, orgID)
+	if err != nil {
+		return 0, err
+	}
+	return sumOrg(quota), nil
+}
+
+func sumOrg(quota []OrgQuotum) int64 {
+	if len(quota) == 0 {
+		return OrgBaseValue
+	}
+	var total int64
+	for _, quotum := range quota {
+		total += quotum.Value
+	}
+	return total
+}
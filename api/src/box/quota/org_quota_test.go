@@ -0,0 +1,28 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumOrg(t *testing.T) {
+	tests := map[string]struct {
+		quota    []OrgQuotum
+		expected int64
+	}{
+		"no quotum limits the org to the base value": {
+			quota:    []OrgQuotum{},
+			expected: OrgBaseValue,
+		},
+		"granted quota replace the base value": {
+			quota:    []OrgQuotum{{Value: 1000}, {Value: 24}},
+			expected: 1024,
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			assert.Equal(t, test.expected, sumOrg(test.quota))
+		})
+	}
+}
@@ -11,6 +11,12 @@ import (
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/uuid"
 )
 
+// every identity is given a base quotum on its first quota listing
+const (
+	BaseOrigin = "base"
+	BaseValue  = int64(104857600)
+)
+
 // Quotum model
 type Quotum struct {
 	ID         string    `json:"id"`
@@ -68,3 +74,24 @@ func List(ctx context.Context, exec boil.ContextExecutor, id string) ([]Quotum,
 	}
 	return quota, nil
 }
+
+// Total storage quota of an identity by summing all its quota
+// the base quotum is counted even if it has not been created yet
+func Total(ctx context.Context, exec boil.ContextExecutor, identityID string) (int64, error) {
+	quota, err := List(ctx, exec, identityID)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	hasBase := false
+	for _, quotum := range quota {
+		total += quotum.Value
+		if quotum.Origin == BaseOrigin {
+			hasBase = true
+		}
+	}
+	if !hasBase {
+		total += BaseValue
+	}
+	return total, nil
+}
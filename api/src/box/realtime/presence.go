@@ -0,0 +1,91 @@
+package realtime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// PresenceTTL is the duration an identity is considered online without any refresh.
+// It protects against counters never decremented because of a crashed instance.
+const PresenceTTL = 90 * time.Second
+
+// PresenceRefreshPeriod is the period at which an open websocket refreshes the presence of its identity.
+const PresenceRefreshPeriod = PresenceTTL / 3
+
+// presenceKey stores the number of open websockets for an identity
+func presenceKey(identityID string) string {
+	return fmt.Sprintf("presence:user_%s", identityID)
+}
+
+// Connect records a new open websocket for the identity.
+// It returns true if the identity has just become online.
+func Connect(redConn *redis.Client, identityID string) (bool, error) {
+	key := presenceKey(identityID)
+	count, err := redConn.Incr(key).Result()
+	if err != nil {
+		return false, err
+	}
+	if _, err := redConn.Expire(key, PresenceTTL).Result(); err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// RefreshPresence extends the presence of the identity.
+func RefreshPresence(redConn *redis.Client, identityID string) error {
+	return redConn.Expire(presenceKey(identityID), PresenceTTL).Err()
+}
+
+// Disconnect records the closing of a websocket for the identity.
+// It returns true if the identity has just become offline.
+func Disconnect(redConn *redis.Client, identityID string) (bool, error) {
+	key := presenceKey(identityID)
+	count, err := redConn.Decr(key).Result()
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	// the key is removed so the presence is not kept alive by a negative counter
+	if _, err := redConn.Del(key).Result(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TypingThrottle is the minimum duration between two typing notifications of an identity in a box
+const TypingThrottle = 3 * time.Second
+
+// typingKey exists while typing notifications of the identity in the box are throttled
+func typingKey(identityID, boxID string) string {
+	return fmt.Sprintf("typing:user_%s:box_%s", identityID, boxID)
+}
+
+// AllowTyping returns true if a typing notification of the identity in the box can be sent,
+// at most one notification is allowed per throttle period.
+func AllowTyping(redConn *redis.Client, identityID, boxID string) (bool, error) {
+	return redConn.SetNX(typingKey(identityID, boxID), 1, TypingThrottle).Result()
+}
+
+// ListOnline returns the online status of the given identities.
+func ListOnline(redConn *redis.Client, identityIDs []string) (map[string]bool, error) {
+	online := make(map[string]bool, len(identityIDs))
+	if len(identityIDs) == 0 {
+		return online, nil
+	}
+	keys := make([]string, len(identityIDs))
+	for i, identityID := range identityIDs {
+		keys[i] = presenceKey(identityID)
+	}
+	values, err := redConn.MGet(keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		online[identityIDs[i]] = (value != nil)
+	}
+	return online, nil
+}
@@ -16,6 +16,27 @@ type Update struct {
 	Object interface{} `json:"object"`
 }
 
+// SendUpdates to several members at once: the update is built once
+// and published to all the members through a single redis round trip
+func SendUpdates(ctx context.Context, redConn *redis.Client, memberIDs []string, update *Update) {
+	if len(memberIDs) == 0 {
+		return
+	}
+	msg, err := json.Marshal(update)
+	if err != nil {
+		logger.FromCtx(ctx).Error().Err(err).Msgf("building update")
+		return
+	}
+	pipe := redConn.Pipeline()
+	for _, memberID := range memberIDs {
+		pipe.Publish(fmt.Sprintf("user_%s:ws", memberID), msg)
+	}
+	logger.FromCtx(ctx).Debug().Msgf("send update to %d users", len(memberIDs))
+	if _, err := pipe.Exec(); err != nil {
+		logger.FromCtx(ctx).Error().Err(err).Msgf("sending update to %d users", len(memberIDs))
+	}
+}
+
 // SendUpdate to member
 func SendUpdate(ctx context.Context, redConn *redis.Client, memberID string, update *Update) {
 	msg, err := json.Marshal(update)
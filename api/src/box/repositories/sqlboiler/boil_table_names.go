@@ -4,19 +4,23 @@
 package sqlboiler
 
 var TableNames = struct {
-	BoxKeyShare   string
-	BoxSetting    string
-	BoxUsedSpace  string
-	EncryptedFile string
-	Event         string
-	SavedFile     string
-	StorageQuotum string
+	BoxKeyShare      string
+	BoxSetting       string
+	BoxUsedSpace     string
+	EncryptedFile    string
+	Event            string
+	OrgStorageQuotum string
+	SavedFile        string
+	SavedFileFolder  string
+	StorageQuotum    string
 }{
-	BoxKeyShare:   "box_key_share",
-	BoxSetting:    "box_setting",
-	BoxUsedSpace:  "box_used_space",
-	EncryptedFile: "encrypted_file",
-	Event:         "event",
-	SavedFile:     "saved_file",
-	StorageQuotum: "storage_quotum",
+	BoxKeyShare:      "box_key_share",
+	BoxSetting:       "box_setting",
+	BoxUsedSpace:     "box_used_space",
+	EncryptedFile:    "encrypted_file",
+	Event:            "event",
+	OrgStorageQuotum: "org_storage_quotum",
+	SavedFile:        "saved_file",
+	SavedFileFolder:  "saved_file_folder",
+	StorageQuotum:    "storage_quotum",
 }
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -23,34 +24,44 @@ import (
 
 // EncryptedFile is an object representing the database table.
 type EncryptedFile struct {
-	ID        string    `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Size      int64     `boil:"size" json:"size" toml:"size" yaml:"size"`
-	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	ID          string      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Size        int64       `boil:"size" json:"size" toml:"size" yaml:"size"`
+	CreatedAt   time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	StorageKey  string      `boil:"storage_key" json:"storage_key" toml:"storage_key" yaml:"storage_key"`
+	ContentHash null.String `boil:"content_hash" json:"content_hash,omitempty" toml:"content_hash" yaml:"content_hash,omitempty"`
 
 	R *encryptedFileR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L encryptedFileL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var EncryptedFileColumns = struct {
-	ID        string
-	Size      string
-	CreatedAt string
+	ID          string
+	Size        string
+	CreatedAt   string
+	StorageKey  string
+	ContentHash string
 }{
-	ID:        "id",
-	Size:      "size",
-	CreatedAt: "created_at",
+	ID:          "id",
+	Size:        "size",
+	CreatedAt:   "created_at",
+	StorageKey:  "storage_key",
+	ContentHash: "content_hash",
 }
 
 // Generated where
 
 var EncryptedFileWhere = struct {
-	ID        whereHelperstring
-	Size      whereHelperint64
-	CreatedAt whereHelpertime_Time
+	ID          whereHelperstring
+	Size        whereHelperint64
+	CreatedAt   whereHelpertime_Time
+	StorageKey  whereHelperstring
+	ContentHash whereHelpernull_String
 }{
-	ID:        whereHelperstring{field: "\"encrypted_file\".\"id\""},
-	Size:      whereHelperint64{field: "\"encrypted_file\".\"size\""},
-	CreatedAt: whereHelpertime_Time{field: "\"encrypted_file\".\"created_at\""},
+	ID:          whereHelperstring{field: "\"encrypted_file\".\"id\""},
+	Size:        whereHelperint64{field: "\"encrypted_file\".\"size\""},
+	CreatedAt:   whereHelpertime_Time{field: "\"encrypted_file\".\"created_at\""},
+	StorageKey:  whereHelperstring{field: "\"encrypted_file\".\"storage_key\""},
+	ContentHash: whereHelpernull_String{field: "\"encrypted_file\".\"content_hash\""},
 }
 
 // EncryptedFileRels is where relationship names are stored.
@@ -74,8 +85,8 @@ func (*encryptedFileR) NewStruct() *encryptedFileR {
 type encryptedFileL struct{}
 
 var (
-	encryptedFileAllColumns            = []string{"id", "size", "created_at"}
-	encryptedFileColumnsWithoutDefault = []string{"id", "size", "created_at"}
+	encryptedFileAllColumns            = []string{"id", "size", "created_at", "storage_key", "content_hash"}
+	encryptedFileColumnsWithoutDefault = []string{"id", "size", "created_at", "storage_key", "content_hash"}
 	encryptedFileColumnsWithDefault    = []string{}
 	encryptedFilePrimaryKeyColumns     = []string{"id"}
 )
@@ -53,6 +53,11 @@ func initService() {
 	// init echo router using sdk call
 	e := echorouter.New(viper.GetString("log.level"))
 	e.HideBanner = true
+	ipExtractor, err := echorouter.NewIPExtractor(viper.GetStringSlice("server.trusted_proxies"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init ip extractor")
+	}
+	e.IPExtractor = ipExtractor
 	pprof.Wrap(e)
 
 	// init modules
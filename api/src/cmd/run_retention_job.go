@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/db"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/jobs"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/identity"
+)
+
+// RetentionJobCmd ...
+var RetentionJobCmd = &cobra.Command{
+	Use:   "retention-job",
+	Short: "Run the retention job",
+	Long:  "This job is responsible for deleting expired messages in boxes.",
+	Run: func(cmd *cobra.Command, args []string) {
+		initRetentionJob()
+	},
+}
+
+func initRetentionJob() {
+	initDefaultRetentionConfig()
+
+	// init logger
+	log.Logger = logger.ZerologLogger(viper.GetString("log.level"))
+	ctx := logger.SetLogger(context.Background(), &log.Logger)
+
+	// init db connections
+	ssoDBConn, err := db.NewPSQLConn(
+		os.Getenv("DSN_SSO"),
+		viper.GetInt("sql.max_open_connections"),
+		viper.GetInt("sql.max_idle_connections"),
+		viper.GetDuration("sql.conn_max_lifetime"),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not connect to db")
+	}
+
+	boxDBConn, err := db.NewPSQLConn(
+		os.Getenv("DSN_BOX"),
+		viper.GetInt("sql.max_open_connections"),
+		viper.GetInt("sql.max_idle_connections"),
+		viper.GetDuration("sql.conn_max_lifetime"),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not connect to db")
+	}
+
+	// init redis connection
+	redConn := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", viper.GetString("redis.address"), viper.GetString("redis.port")),
+		Password: "",
+		DB:       0,
+	})
+	if _, err := redConn.Ping().Result(); err != nil {
+		log.Fatal().Err(err).Msg("could not connect to redis")
+	}
+
+	filesRepo, err := box.NewFileStorageRepo()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init files storage")
+	}
+
+	retentionJob := jobs.NewRetentionJob(
+		boxDBConn, redConn, filesRepo,
+		identity.NewIntraprocessHelper(ssoDBConn, redConn),
+	)
+	if err := retentionJob.ExpireMessages(ctx); err != nil {
+		log.Error().Err(err).Msg("could not expire messages")
+	}
+}
+
+func initDefaultRetentionConfig() {
+	// always look for the configuration file in the /etc folder
+	env := os.Getenv("ENV")
+	if env == "development" {
+		viper.SetConfigName("api-config.dev")
+	} else {
+		viper.SetConfigName("api-config")
+	}
+	viper.AddConfigPath("/etc/")
+
+	// set defaults value for configuration
+	// some of these fields are shared between modules.
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("sql.max_open_connections", 15)
+	viper.SetDefault("sql.max_idle_connections", 15)
+	viper.SetDefault("sql.conn_max_lifetime", "5m")
+
+	// try reading in a config
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal().Err(err).Msg("could not read configuration")
+	}
+
+	mandatoryFields := []string{
+		"redis.address",
+		"redis.port",
+	}
+	mandatoryFields = append(mandatoryFields, config.StorageMandatoryFields(
+		[]string{"server.encrypted_files"},
+		[]string{"aws.encrypted_files_bucket"},
+		[]string{"s3.encrypted_files_bucket"},
+	)...)
+	config.FatalIfMissing("Retention", mandatoryFields)
+	config.Print("Retention", []string{})
+}
+
+func init() {
+	RootCmd.AddCommand(RetentionJobCmd)
+}
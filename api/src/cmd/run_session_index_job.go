@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/mredis"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
+)
+
+// SessionIndexJobCmd ...
+var SessionIndexJobCmd = &cobra.Command{
+	Use:   "session-index-job",
+	Short: "Run the authn sessions index job",
+	Long: `This job indexes per identity the authn sessions stored before they were indexed,
+so they can be listed and revoked by their identity. It must be run once after the upgrade
+and can safely be run again.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return initSessionIndexJob()
+	},
+}
+
+func initSessionIndexJob() error {
+	initDefaultSessionIndexConfig()
+
+	// init logger
+	log.Logger = logger.ZerologLogger(viper.GetString("log.level"))
+	ctx := logger.SetLogger(context.Background(), &log.Logger)
+
+	// init redis connection
+	redConn := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", viper.GetString("redis.address"), viper.GetString("redis.port")),
+		Password: "",
+		DB:       0,
+	})
+	if _, err := redConn.Ping().Result(); err != nil {
+		log.Fatal().Err(err).Msg("could not connect to redis")
+	}
+
+	authnSessionRepo := authn.NewAuthnSessionRedis(mredis.NewSimpleKeyRedis(redConn), redConn)
+	if err := authnSessionRepo.IndexLegacySessions(ctx); err != nil {
+		return fmt.Errorf("indexing authn sessions: %w", err)
+	}
+	return nil
+}
+
+func initDefaultSessionIndexConfig() {
+	// always look for the configuration file in the /etc folder
+	env := os.Getenv("ENV")
+	if env == "development" {
+		viper.SetConfigName("api-config.dev")
+	} else {
+		viper.SetConfigName("api-config")
+	}
+	viper.AddConfigPath("/etc/")
+
+	// set defaults value for configuration
+	viper.SetDefault("log.level", "info")
+
+	// try reading in a config
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal().Err(err).Msg("could not read configuration")
+	}
+
+	mandatoryFields := []string{
+		"redis.address",
+		"redis.port",
+	}
+	config.FatalIfMissing("SessionIndex", mandatoryFields)
+	config.Print("SessionIndex", []string{})
+}
+
+func init() {
+	RootCmd.AddCommand(SessionIndexJobCmd)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/db"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box"
+	"gitlab.misakey.dev/misakey/backend/api/src/box/jobs"
+)
+
+var (
+	storageCheckRepair      bool
+	storageCheckGracePeriod time.Duration
+)
+
+// StorageCheckJobCmd ...
+var StorageCheckJobCmd = &cobra.Command{
+	Use:   "storage-check-job",
+	Short: "Run the storage check job",
+	Long: `This job checks the consistency between the encrypted files storage and the box database.
+It reports orphan stored data, encrypted files without data or referrer and wrong box used spaces,
+then repairs them if --repair is set. The report is written on the standard output
+and the command fails if the check could not be completed.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return initStorageCheckJob()
+	},
+}
+
+func initStorageCheckJob() error {
+	initDefaultStorageCheckConfig()
+
+	// init logger
+	log.Logger = logger.ZerologLogger(viper.GetString("log.level"))
+	ctx := logger.SetLogger(context.Background(), &log.Logger)
+
+	// init db connection
+	boxDBConn, err := db.NewPSQLConn(
+		os.Getenv("DSN_BOX"),
+		viper.GetInt("sql.max_open_connections"),
+		viper.GetInt("sql.max_idle_connections"),
+		viper.GetDuration("sql.conn_max_lifetime"),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not connect to db")
+	}
+
+	filesRepo, err := box.NewFileStorageRepo()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init files storage")
+	}
+
+	storageCheckJob := jobs.NewStorageCheckJob(boxDBConn, filesRepo, storageCheckGracePeriod, storageCheckRepair)
+	return runStorageCheck(ctx, storageCheckJob, os.Stdout)
+}
+
+type storageChecker interface {
+	Check(ctx context.Context) (jobs.StorageReport, error)
+}
+
+// runStorageCheck writes the report, even partial, and returns an error if the check has failed
+func runStorageCheck(ctx context.Context, checker storageChecker, w io.Writer) error {
+	report, checkErr := checker.Check(ctx)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	if checkErr != nil {
+		return fmt.Errorf("checking storage: %w", checkErr)
+	}
+	return nil
+}
+
+func initDefaultStorageCheckConfig() {
+	// always look for the configuration file in the /etc folder
+	env := os.Getenv("ENV")
+	if env == "development" {
+		viper.SetConfigName("api-config.dev")
+	} else {
+		viper.SetConfigName("api-config")
+	}
+	viper.AddConfigPath("/etc/")
+
+	// set defaults value for configuration
+	// some of these fields are shared between modules.
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("sql.max_open_connections", 15)
+	viper.SetDefault("sql.max_idle_connections", 15)
+	viper.SetDefault("sql.conn_max_lifetime", "5m")
+
+	// try reading in a config
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatal().Err(err).Msg("could not read configuration")
+	}
+
+	mandatoryFields := config.StorageMandatoryFields(
+		[]string{"server.encrypted_files"},
+		[]string{"aws.encrypted_files_bucket"},
+		[]string{"s3.encrypted_files_bucket"},
+	)
+	config.FatalIfMissing("StorageCheck", mandatoryFields)
+	config.Print("StorageCheck", []string{})
+}
+
+func init() {
+	// the grace period must exceed the lifetime of upload sessions and direct uploads
+	StorageCheckJobCmd.Flags().BoolVar(&storageCheckRepair, "repair", false, "repair the inconsistencies")
+	StorageCheckJobCmd.Flags().DurationVar(&storageCheckGracePeriod, "grace-period", 48*time.Hour, "ignore files more recent than this period")
+	RootCmd.AddCommand(StorageCheckJobCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/box/jobs"
+)
+
+type fakeChecker struct {
+	report jobs.StorageReport
+	err    error
+}
+
+func (c fakeChecker) Check(_ context.Context) (jobs.StorageReport, error) {
+	return c.report, c.err
+}
+
+func TestRunStorageCheck(t *testing.T) {
+	tests := map[string]struct {
+		checker     fakeChecker
+		expectedErr bool
+	}{
+		"successful check": {
+			checker: fakeChecker{report: jobs.StorageReport{OrphanFiles: []string{"file-1"}}},
+		},
+		"failed check": {
+			checker:     fakeChecker{report: jobs.StorageReport{OrphanFiles: []string{"file-1"}}, err: errors.New("database unreachable")},
+			expectedErr: true,
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runStorageCheck(context.Background(), test.checker, &out)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			// the report is written even if the check has failed
+			assert.Contains(t, out.String(), `"orphan_files":["file-1"]`)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+
+	"github.com/spf13/viper"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/ms3"
+)
+
+// storage backends of files (box encrypted files, avatars...)
+const (
+	FileSystemStorage   = "filesystem"
+	AmazonS3Storage     = "aws_s3"
+	S3CompatibleStorage = "s3_compatible"
+)
+
+// StorageBackend returns the configured storage backend.
+// By default, the file system is used in development and Amazon S3 in production.
+func StorageBackend() string {
+	if backend := viper.GetString("storage.backend"); backend != "" {
+		return backend
+	}
+	if os.Getenv("ENV") == "development" {
+		return FileSystemStorage
+	}
+	return AmazonS3Storage
+}
+
+// S3Config returns the configuration of the storage for S3 backends.
+// Credentials of S3-compatible storages are read from S3_ACCESS_KEY and S3_SECRET_KEY environment variables.
+func S3Config() ms3.Config {
+	if StorageBackend() == AmazonS3Storage {
+		return ms3.Config{Region: viper.GetString("aws.s3_region")}
+	}
+	return ms3.Config{
+		Region:             viper.GetString("s3.region"),
+		Endpoint:           viper.GetString("s3.endpoint"),
+		PathStyle:          viper.GetBool("s3.path_style"),
+		AccessKey:          os.Getenv("S3_ACCESS_KEY"),
+		SecretKey:          os.Getenv("S3_SECRET_KEY"),
+		DisableTLS:         viper.GetBool("s3.disable_tls"),
+		InsecureSkipVerify: viper.GetBool("s3.insecure_skip_verify"),
+	}
+}
+
+// StorageMandatoryFields returns configuration fields required by the storage backend.
+// Fields specific to the caller module (locations, buckets...) are given for each backend.
+func StorageMandatoryFields(fileSystemFields, awsFields, s3Fields []string) []string {
+	switch StorageBackend() {
+	case FileSystemStorage:
+		return fileSystemFields
+	case AmazonS3Storage:
+		return append([]string{"aws.s3_region"}, awsFields...)
+	case S3CompatibleStorage:
+		return append([]string{"s3.region", "s3.endpoint"}, s3Fields...)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+// UploadLimits are the maximum sizes in bytes of uploaded files
+type UploadLimits struct {
+	// encrypted files sent to boxes - organizations can override it
+	EncryptedFile int64
+	// encrypted files uploaded to the vault
+	SavedFile int64
+	Avatar    int64
+	// hard maximum of the file sizes organizations can set as overrides
+	OrgMaxFileSize int64
+}
+
+// default upload limits used if they are not configured
+const (
+	DefaultEncryptedFileMaxSize int64 = 126 * 1024 * 1024
+	DefaultSavedFileMaxSize     int64 = 126 * 1024 * 1024
+	DefaultAvatarMaxSize        int64 = 3 * 1024 * 1024
+	DefaultOrgMaxFileSize       int64 = 2 * 1024 * 1024 * 1024
+)
+
+// GetUploadLimits reads the upload_limits section of the configuration.
+// Sizes are expressed with a unit (ex: "126MB").
+func GetUploadLimits() UploadLimits {
+	return UploadLimits{
+		EncryptedFile:  sizeInBytes("upload_limits.encrypted_file", DefaultEncryptedFileMaxSize),
+		SavedFile:      sizeInBytes("upload_limits.saved_file", DefaultSavedFileMaxSize),
+		Avatar:         sizeInBytes("upload_limits.avatar", DefaultAvatarMaxSize),
+		OrgMaxFileSize: sizeInBytes("upload_limits.org_max_file", DefaultOrgMaxFileSize),
+	}
+}
+
+func sizeInBytes(key string, defaultValue int64) int64 {
+	if !viper.IsSet(key) {
+		return defaultValue
+	}
+	return int64(viper.GetSizeInBytes(key))
+}
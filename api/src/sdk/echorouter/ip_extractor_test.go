@@ -0,0 +1,46 @@
+package echorouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIPExtractor(t *testing.T) {
+	tests := map[string]struct {
+		trustedProxies []string
+		remoteAddr     string
+		expected       string
+	}{
+		"no trusted proxy ignores the header": {
+			remoteAddr: "10.0.0.2:1234",
+			expected:   "10.0.0.2",
+		},
+		"trusted proxy forwards the client address": {
+			trustedProxies: []string{"10.0.0.0/24"},
+			remoteAddr:     "10.0.0.2:1234",
+			expected:       "203.0.113.12",
+		},
+		"untrusted peer cannot spoof the address": {
+			trustedProxies: []string{"10.0.0.0/24"},
+			remoteAddr:     "192.168.1.5:1234",
+			expected:       "192.168.1.5",
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			extractor, err := NewIPExtractor(test.trustedProxies)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set("X-Forwarded-For", "203.0.113.12")
+			assert.Equal(t, test.expected, extractor(req))
+		})
+	}
+
+	_, err := NewIPExtractor([]string{"not-a-range"})
+	assert.Error(t, err)
+}
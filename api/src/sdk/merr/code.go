@@ -12,19 +12,22 @@ type Code string
 // code constants
 const (
 	// classic codes
-	BadRequestCode            Code = "bad_request"
-	UnauthorizedCode          Code = "unauthorized"
-	ForbiddenCode             Code = "forbidden"
-	NotFoundCode              Code = "not_found"
-	MethodNotAllowedCode      Code = "method_not_allowed"
-	ConflictCode              Code = "conflict"
-	GoneCode                  Code = "gone"
-	RequestEntityTooLargeCode Code = "request_entity_too_large"
-	UnprocessableEntityCode   Code = "unprocessable_entity"
-	ClientClosedRequestCode   Code = "client_closed_requiest"
-	InternalCode              Code = "internal"
-	BadGatewayCode            Code = "bad_gateway"
-	ServiceUnavailableCode    Code = "service_unavailable"
+	BadRequestCode                   Code = "bad_request"
+	UnauthorizedCode                 Code = "unauthorized"
+	ForbiddenCode                    Code = "forbidden"
+	NotFoundCode                     Code = "not_found"
+	MethodNotAllowedCode             Code = "method_not_allowed"
+	ConflictCode                     Code = "conflict"
+	GoneCode                         Code = "gone"
+	RequestEntityTooLargeCode        Code = "request_entity_too_large"
+	RequestedRangeNotSatisfiableCode Code = "requested_range_not_satisfiable"
+	QuotaExceededCode                Code = "quota_exceeded"
+	TooManyRequestsCode              Code = "too_many_requests"
+	UnprocessableEntityCode          Code = "unprocessable_entity"
+	ClientClosedRequestCode          Code = "client_closed_requiest"
+	InternalCode                     Code = "internal"
+	BadGatewayCode                   Code = "bad_gateway"
+	ServiceUnavailableCode           Code = "service_unavailable"
 
 	// no_code codes
 	UnknownCode Code = "unknown_code"
@@ -85,6 +88,12 @@ func ToCode(err error) Code {
 		return ConflictCode
 	case ErrRequestEntityTooLarge:
 		return RequestEntityTooLargeCode
+	case ErrRequestedRangeNotSatisfiable:
+		return RequestedRangeNotSatisfiableCode
+	case ErrQuotaExceeded:
+		return QuotaExceededCode
+	case ErrTooManyRequests:
+		return TooManyRequestsCode
 	case ErrUnprocessableEntity:
 		return UnprocessableEntityCode
 	case ErrClientClosedRequest:
@@ -111,6 +120,14 @@ func IsAConflict(err error) bool {
 	return hasCode(err, ConflictCode)
 }
 
+func IsAQuotaExceeded(err error) bool {
+	return hasCode(err, QuotaExceededCode)
+}
+
+func IsATooManyRequests(err error) bool {
+	return hasCode(err, TooManyRequestsCode)
+}
+
 func IsAnInternal(err error) bool {
 	return hasCode(err, InternalCode)
 }
@@ -22,6 +22,12 @@ var (
 	ErrGone = errors.New("gone")
 	// ErrRequestEntityTooLarge ...
 	ErrRequestEntityTooLarge = errors.New("request entity too large")
+	// ErrRequestedRangeNotSatisfiable ...
+	ErrRequestedRangeNotSatisfiable = errors.New("requested range not satisfiable")
+	// ErrQuotaExceeded ...
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrTooManyRequests ...
+	ErrTooManyRequests = errors.New("too many requests")
 	// ErrUnprocessableEntity ...
 	ErrUnprocessableEntity = errors.New("unprocessable entity")
 	// ErrClientClosedRequest ...
@@ -104,6 +110,36 @@ func RequestEntityTooLarge() Error {
 	}
 }
 
+// RequestedRangeNotSatisfiable ...
+func RequestedRangeNotSatisfiable() Error {
+	return Error{
+		error:   ErrRequestedRangeNotSatisfiable,
+		Co:      RequestedRangeNotSatisfiableCode,
+		Origin:  OriNotDefined,
+		Details: make(map[string]string),
+	}
+}
+
+// QuotaExceeded ...
+func QuotaExceeded() Error {
+	return Error{
+		error:   ErrQuotaExceeded,
+		Co:      QuotaExceededCode,
+		Origin:  OriNotDefined,
+		Details: make(map[string]string),
+	}
+}
+
+// TooManyRequests ...
+func TooManyRequests() Error {
+	return Error{
+		error:   ErrTooManyRequests,
+		Co:      TooManyRequestsCode,
+		Origin:  OriNotDefined,
+		Details: make(map[string]string),
+	}
+}
+
 // UnprocessableEntity ...
 func UnprocessableEntity() Error {
 	return Error{
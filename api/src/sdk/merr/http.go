@@ -29,6 +29,12 @@ func ToHTTPCode(err error) int {
 		return http.StatusGone
 	case RequestEntityTooLargeCode:
 		return http.StatusRequestEntityTooLarge
+	case RequestedRangeNotSatisfiableCode:
+		return http.StatusRequestedRangeNotSatisfiable
+	case QuotaExceededCode:
+		return http.StatusInsufficientStorage
+	case TooManyRequestsCode:
+		return http.StatusTooManyRequests
 	case UnprocessableEntityCode:
 		return http.StatusUnprocessableEntity
 	case ClientClosedRequestCode:
@@ -58,6 +64,12 @@ func TransformHTTPCode(code int) Error {
 		return Conflict()
 	case http.StatusRequestEntityTooLarge:
 		return RequestEntityTooLarge()
+	case http.StatusRequestedRangeNotSatisfiable:
+		return RequestedRangeNotSatisfiable()
+	case http.StatusInsufficientStorage:
+		return QuotaExceeded()
+	case http.StatusTooManyRequests:
+		return TooManyRequests()
 	case http.StatusUnprocessableEntity:
 		return UnprocessableEntity()
 	case StatusClientClosedRequest:
@@ -64,6 +64,18 @@ func TestTransformHTTPCode(t *testing.T) {
 			code:        499,
 			expectedErr: ClientClosedRequest(),
 		},
+		"RequestedRangeNotSatisfiable": {
+			code:        416,
+			expectedErr: RequestedRangeNotSatisfiable(),
+		},
+		"QuotaExceeded": {
+			code:        507,
+			expectedErr: QuotaExceeded(),
+		},
+		"TooManyRequests": {
+			code:        429,
+			expectedErr: TooManyRequests(),
+		},
 	}
 	for description, test := range tests {
 		t.Run(description, func(t *testing.T) {
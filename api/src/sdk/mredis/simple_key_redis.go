@@ -56,9 +56,15 @@ func (skr *SimpleKeyRedis) MustFind(ctx context.Context, matchKey string) ([][]b
 		return nil, err
 	}
 
-	ret := make([][]byte, len(results))
-	for i, elem := range results {
-		ret[i] = []byte(elem.(string))
+	ret := make([][]byte, 0, len(results))
+	for _, elem := range results {
+		// keys might have expired since they have been found
+		if value, ok := elem.(string); ok {
+			ret = append(ret, []byte(value))
+		}
+	}
+	if len(ret) == 0 {
+		return nil, merr.NotFound()
 	}
 	return ret, nil
 }
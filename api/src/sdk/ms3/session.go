@@ -0,0 +1,50 @@
+package ms3
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// Config of an S3-compatible object storage: Amazon S3, MinIO, Scaleway, Ceph...
+type Config struct {
+	Region string
+	// Endpoint of the storage - Amazon S3 is used if empty
+	Endpoint string
+	// PathStyle addressing uses {endpoint}/{bucket}/{key} instead of {bucket}.{endpoint}/{key}
+	PathStyle bool
+
+	// static credentials - the default AWS credential chain is used if empty
+	AccessKey string
+	SecretKey string
+
+	// DisableTLS to talk to the endpoint using plain http
+	DisableTLS bool
+	// InsecureSkipVerify disables the verification of the endpoint certificate
+	InsecureSkipVerify bool
+}
+
+// NewSession for the configured storage
+func NewSession(cfg Config) (*session.Session, error) {
+	awsCfg := &aws.Config{
+		Region:           aws.String(cfg.Region),
+		S3ForcePathStyle: aws.Bool(cfg.PathStyle),
+		DisableSSL:       aws.Bool(cfg.DisableTLS),
+	}
+	if cfg.Endpoint != "" {
+		awsCfg.Endpoint = aws.String(cfg.Endpoint)
+	}
+	if cfg.AccessKey != "" {
+		awsCfg.Credentials = credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, "")
+	}
+	if cfg.InsecureSkipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		// only meant for on-premise endpoints with self-signed certificates
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		awsCfg.HTTPClient = &http.Client{Transport: transport}
+	}
+	return session.NewSession(awsCfg)
+}
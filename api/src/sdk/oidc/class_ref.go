@@ -62,50 +62,20 @@ func (acrs *ClassRefs) Set(acr ClassRef) {
 	*acrs = ClassRefs([]ClassRef{acr})
 }
 
-// RememberFor return an integer corresponding to seconds, according to the authentication context class
+// RememberFor return an integer corresponding to seconds, according to the instance policy
 func (acr ClassRef) RememberFor() int {
-	switch acr {
-	case ACR1:
-		return 3600 // 1h
-	case ACR2, ACR3, ACR4:
-		return 2592000 // 30d
-	}
-	return 0
-}
-
-var methodToACR = map[MethodRef]ClassRef{
-	AMREmailedCode:       ACR1,
-	AMRPrehashedPassword: ACR2,
-	AMRTOTP:              ACR3,
-	AMRWebauthn:          ACR4,
+	return policy.RememberFor(acr)
 }
 
-// GetMethodACR based on the static map above
+// GetMethodACR based on the instance policy
 func GetMethodACR(methodRefStr string) ClassRef {
-	return methodToACR[MethodRef(methodRefStr)]
-}
-
-var acrToMethod = map[ClassRef]MethodRef{
-	ACR1: AMREmailedCode,
-	ACR2: AMRPrehashedPassword,
-	ACR3: AMRTOTP,
-	ACR4: AMRWebauthn,
+	return policy.MethodACR(MethodRef(methodRefStr))
 }
 
-// GetNextMethord returns next expected authn method
+// GetNextMethod returns next expected authn method according to the instance policy
 // and return nil if no next method is expected
 func GetNextMethod(currentACR ClassRef, expectedACR ClassRef) *MethodRef {
-	// if the current ACR equals or is higher than the expected,
-	// there is no more authn method to perform
-	if acrToInt[currentACR] >= acrToInt[expectedACR] {
-		return nil
-	}
-
-	method, ok := acrToMethod[expectedACR]
-	if ok {
-		return &method
-	}
-	return nil
+	return policy.NextMethod(currentACR, expectedACR)
 }
 
 // IsJustBefore returns true if the current ACR
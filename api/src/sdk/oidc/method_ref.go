@@ -26,6 +26,10 @@ const (
 	AMRTOTP MethodRef = "totp"
 	// AMRWebauthn is the use of webauthn protocol
 	AMRWebauthn MethodRef = "webauthn"
+	// AMRUserVerification is the verification of the user by a webauthn authenticator (biometrics, PIN...)
+	AMRUserVerification MethodRef = "user_verification"
+	// AMRFederatedOIDC is the authentication by an upstream OpenID Connect provider
+	AMRFederatedOIDC MethodRef = "federated_oidc"
 )
 
 // Add ...
@@ -43,40 +47,9 @@ func (amrs MethodRefs) Has(method MethodRef) bool {
 	return false
 }
 
-// ToACR ...
-// password + webauthn = acr 4
-// password + totp: acr 3
-// password, account creation: acr 2
-// emailed code: acr 1
+// ToACR according to the instance policy - see DefaultPolicy for the default combinations
 func (amrs MethodRefs) ToACR() ClassRef {
-	// acr 4
-	if amrs.Includes(AMRWebauthn, AMRPrehashedPassword) ||
-		amrs.Includes(AMRWebauthn, AMREmailedCode, AMRResetPassword) {
-		return ACR4
-	}
-
-	// acr 3
-	if amrs.Includes(AMRTOTP, AMRPrehashedPassword) ||
-		amrs.Includes(AMRTOTP, AMREmailedCode, AMRResetPassword) ||
-		amrs.Includes(AMRWebauthn, AMREmailedCode) {
-		return ACR3
-	}
-
-	// acr 2
-	if amrs.Has(AMRPrehashedPassword) ||
-		amrs.Has(AMRAccountCreation) ||
-		amrs.Includes(AMREmailedCode, AMRResetPassword) ||
-		amrs.Includes(AMREmailedCode, AMRTOTP) {
-		return ACR2
-	}
-
-	// acr 1
-	if amrs.Has(AMREmailedCode) {
-		return ACR1
-	}
-
-	// acr 0
-	return ACR0
+	return policy.ToACR(amrs)
 }
 
 // String ...
@@ -0,0 +1,31 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToACR(t *testing.T) {
+	tests := map[string]struct {
+		amrs     MethodRefs
+		expected ClassRef
+	}{
+		"nothing":                       {amrs: MethodRefs{}, expected: ACR0},
+		"emailed code":                  {amrs: MethodRefs{AMREmailedCode}, expected: ACR1},
+		"password":                      {amrs: MethodRefs{AMRPrehashedPassword}, expected: ACR2},
+		"password and totp":             {amrs: MethodRefs{AMRPrehashedPassword, AMRTOTP}, expected: ACR3},
+		"emailed code and webauthn":     {amrs: MethodRefs{AMREmailedCode, AMRWebauthn}, expected: ACR3},
+		"password and webauthn":         {amrs: MethodRefs{AMRPrehashedPassword, AMRWebauthn}, expected: ACR4},
+		"webauthn alone":                {amrs: MethodRefs{AMRWebauthn}, expected: ACR0},
+		"webauthn with verified user":   {amrs: MethodRefs{AMRUserVerification, AMRWebauthn}, expected: ACR4},
+		"user verification without key": {amrs: MethodRefs{AMRUserVerification}, expected: ACR0},
+		"upstream provider":             {amrs: MethodRefs{AMRFederatedOIDC}, expected: ACR2},
+		"upstream provider and totp":    {amrs: MethodRefs{AMRFederatedOIDC, AMRTOTP}, expected: ACR3},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.amrs.ToACR())
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package oidc
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policy describes how authentication methods are combined to reach each ACR
+// and how long the corresponding login sessions are remembered.
+// A policy is configured for the whole instance and can be restricted for some identities
+// (ex: members of an organization) using a PolicyRestriction.
+type Policy struct {
+	// Levels for each ACR - an ACR without level cannot be reached
+	Levels map[ClassRef]Level `json:"levels" mapstructure:"levels"`
+	// MinACR is the minimum ACR required to login
+	MinACR ClassRef `json:"min_acr" mapstructure:"min_acr"`
+}
+
+// Level describes how to reach an ACR
+type Level struct {
+	// Method asked to the end-user when the ACR is expected
+	Method MethodRef `json:"method" mapstructure:"method"`
+	// Combinations of methods granting the ACR - any of them is enough
+	Combinations []MethodRefs `json:"combinations" mapstructure:"combinations"`
+	// RememberFor is the lifetime of the login session - zero means the session is not remembered
+	RememberFor time.Duration `json:"remember_for" mapstructure:"remember_for"`
+}
+
+// PolicyRestriction makes a policy stricter for some identities
+type PolicyRestriction struct {
+	// MinACR is the minimum ACR required to login - used to enforce a MFA method
+	MinACR ClassRef `json:"min_acr"`
+	// RememberFor shortens the lifetime of login sessions per ACR, it is expressed in seconds
+	RememberFor map[ClassRef]int `json:"remember_for"`
+}
+
+// DefaultPolicy is the historical ACR ladder:
+// emailed code: acr 1
+// password, account creation, upstream provider: acr 2
+// password or upstream provider + totp: acr 3
+// password or upstream provider + webauthn, webauthn with user verification: acr 4
+func DefaultPolicy() Policy {
+	month := 30 * 24 * time.Hour
+	return Policy{
+		MinACR: ACR0,
+		Levels: map[ClassRef]Level{
+			ACR1: {
+				Method: AMREmailedCode,
+				Combinations: []MethodRefs{
+					{AMREmailedCode},
+				},
+				RememberFor: time.Hour,
+			},
+			ACR2: {
+				Method: AMRPrehashedPassword,
+				Combinations: []MethodRefs{
+					{AMRPrehashedPassword},
+					{AMRAccountCreation},
+					{AMREmailedCode, AMRResetPassword},
+					{AMREmailedCode, AMRTOTP},
+					{AMRFederatedOIDC},
+				},
+				RememberFor: month,
+			},
+			ACR3: {
+				Method: AMRTOTP,
+				Combinations: []MethodRefs{
+					{AMRTOTP, AMRPrehashedPassword},
+					{AMRTOTP, AMREmailedCode, AMRResetPassword},
+					{AMRWebauthn, AMREmailedCode},
+					{AMRTOTP, AMRFederatedOIDC},
+				},
+				RememberFor: month,
+			},
+			ACR4: {
+				Method: AMRWebauthn,
+				Combinations: []MethodRefs{
+					{AMRWebauthn, AMRPrehashedPassword},
+					{AMRWebauthn, AMREmailedCode, AMRResetPassword},
+					{AMRWebauthn, AMRUserVerification},
+					{AMRWebauthn, AMRFederatedOIDC},
+				},
+				RememberFor: month,
+			},
+		},
+	}
+}
+
+// policy used by the instance
+var policy = DefaultPolicy()
+
+// SetPolicy used by the instance - it must be called on start
+func SetPolicy(p Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	policy = p
+	return nil
+}
+
+// GetPolicy used by the instance
+func GetPolicy() Policy {
+	return policy
+}
+
+// Validate the consistency of the policy
+func (p Policy) Validate() error {
+	if _, ok := acrToInt[p.MinACR]; !ok && p.MinACR != "" {
+		return fmt.Errorf("unknown min acr %q", p.MinACR)
+	}
+	for acr, level := range p.Levels {
+		if _, ok := acrToInt[acr]; !ok || acr == ACR0 {
+			return fmt.Errorf("unknown acr %q", acr)
+		}
+		if level.Method == "" {
+			return fmt.Errorf("acr %s: method is required", acr)
+		}
+		if len(level.Combinations) == 0 {
+			return fmt.Errorf("acr %s: at least one combination is required", acr)
+		}
+		if level.RememberFor < 0 {
+			return fmt.Errorf("acr %s: remember_for cannot be negative", acr)
+		}
+	}
+	return nil
+}
+
+// ToACR returns the highest ACR granted by the methods
+func (p Policy) ToACR(amrs MethodRefs) ClassRef {
+	max := ACR0
+	for acr, level := range p.Levels {
+		if !max.LessThan(acr) {
+			continue
+		}
+		for _, combination := range level.Combinations {
+			if amrs.Includes(combination...) {
+				max = acr
+				break
+			}
+		}
+	}
+	return max
+}
+
+// NextMethod returns next expected authn method
+// and return nil if no next method is expected
+func (p Policy) NextMethod(currentACR, expectedACR ClassRef) *MethodRef {
+	// if the current ACR equals or is higher than the expected,
+	// there is no more authn method to perform
+	if !currentACR.LessThan(expectedACR) {
+		return nil
+	}
+
+	level, ok := p.Levels[expectedACR]
+	if !ok {
+		return nil
+	}
+	return &level.Method
+}
+
+// MethodACR returns the lowest ACR asking for the method
+// ACR0 is returned if no ACR asks for it
+func (p Policy) MethodACR(method MethodRef) ClassRef {
+	min := ACR0
+	for acr, level := range p.Levels {
+		if level.Method == method && (min == ACR0 || acr.LessThan(min)) {
+			min = acr
+		}
+	}
+	return min
+}
+
+// RememberFor return an integer corresponding to seconds, according to the authentication context class
+func (p Policy) RememberFor(acr ClassRef) int {
+	return int(p.Levels[acr].RememberFor / time.Second)
+}
+
+// Restrict the policy: the highest minimum ACR and the shortest login sessions are kept
+func (p Policy) Restrict(restriction PolicyRestriction) Policy {
+	restricted := Policy{
+		MinACR: p.MinACR,
+		Levels: make(map[ClassRef]Level, len(p.Levels)),
+	}
+	if restricted.MinACR.LessThan(restriction.MinACR) {
+		restricted.MinACR = restriction.MinACR
+	}
+	for acr, level := range p.Levels {
+		if seconds, ok := restriction.RememberFor[acr]; ok {
+			rememberFor := time.Duration(seconds) * time.Second
+			if rememberFor < level.RememberFor {
+				level.RememberFor = rememberFor
+			}
+		}
+		restricted.Levels[acr] = level
+	}
+	return restricted
+}
+
+// Validate the restriction values
+func (restriction PolicyRestriction) Validate() error {
+	if _, ok := acrToInt[restriction.MinACR]; !ok && restriction.MinACR != "" {
+		return fmt.Errorf("unknown min acr %q", restriction.MinACR)
+	}
+	for acr, seconds := range restriction.RememberFor {
+		if _, ok := acrToInt[acr]; !ok {
+			return fmt.Errorf("unknown acr %q", acr)
+		}
+		if seconds < 0 {
+			return fmt.Errorf("acr %s: remember_for cannot be negative", acr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package oidc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyNextMethod(t *testing.T) {
+	p := DefaultPolicy()
+	password, totp := AMRPrehashedPassword, AMRTOTP
+	tests := map[string]struct {
+		current  ClassRef
+		expected ClassRef
+		method   *MethodRef
+	}{
+		"already reached": {current: ACR3, expected: ACR2, method: nil},
+		"password":        {current: ACR1, expected: ACR2, method: &password},
+		"totp":            {current: ACR2, expected: ACR3, method: &totp},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			assert.Equal(t, test.method, p.NextMethod(test.current, test.expected))
+		})
+	}
+
+	// a level missing from the policy cannot be reached
+	delete(p.Levels, ACR3)
+	assert.Nil(t, p.NextMethod(ACR2, ACR3))
+}
+
+func TestPolicyMethodACR(t *testing.T) {
+	p := DefaultPolicy()
+	assert.Equal(t, ACR1, p.MethodACR(AMREmailedCode))
+	assert.Equal(t, ACR3, p.MethodACR(AMRTOTP))
+	assert.Equal(t, ACR4, p.MethodACR(AMRWebauthn))
+	assert.Equal(t, ACR0, p.MethodACR(AMRResetPassword))
+}
+
+func TestPolicyRestrict(t *testing.T) {
+	p := DefaultPolicy()
+	restricted := p.Restrict(PolicyRestriction{
+		MinACR: ACR3,
+		RememberFor: map[ClassRef]int{
+			ACR1: 7200,
+			ACR3: 3600,
+		},
+	})
+	// the highest min acr is kept
+	assert.Equal(t, ACR3, restricted.MinACR)
+	// the shortest session lifetimes are kept
+	assert.Equal(t, 3600, restricted.RememberFor(ACR1))
+	assert.Equal(t, 3600, restricted.RememberFor(ACR3))
+	assert.Equal(t, p.RememberFor(ACR2), restricted.RememberFor(ACR2))
+	// the original policy is untouched
+	assert.Equal(t, ACR0, p.MinACR)
+	assert.Equal(t, int(30*24*time.Hour/time.Second), p.RememberFor(ACR3))
+
+	// a restriction cannot lower the min acr
+	assert.Equal(t, ACR3, restricted.Restrict(PolicyRestriction{MinACR: ACR1}).MinACR)
+}
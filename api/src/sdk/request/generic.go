@@ -30,6 +30,8 @@ func ResponseNoContent(eCtx echo.Context, _ interface{}) error {
 }
 
 // ResponseStream ...
+// If the stream fails once the response has started, the connection is aborted
+// so the client sees a failed download instead of a complete but truncated body.
 func ResponseStream(eCtx echo.Context, data interface{}) error {
 	readCloser := data.(io.ReadCloser)
 	defer func(ctx context.Context) {
@@ -38,7 +40,27 @@ func ResponseStream(eCtx echo.Context, data interface{}) error {
 		}
 	}(eCtx.Request().Context())
 
-	return eCtx.Stream(http.StatusOK, echo.MIMEOctetStream, readCloser)
+	err := eCtx.Stream(http.StatusOK, echo.MIMEOctetStream, readCloser)
+	if err != nil && eCtx.Response().Committed {
+		abortConnection(eCtx, err)
+		return nil
+	}
+	return err
+}
+
+// abortConnection closes the underlying connection without ending the response properly
+func abortConnection(eCtx echo.Context, cause error) {
+	logger.FromCtx(eCtx.Request().Context()).Error().Err(cause).Msg("aborting stream")
+	hijacker, ok := eCtx.Response().Writer.(http.Hijacker)
+	if !ok {
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		logger.FromCtx(eCtx.Request().Context()).Error().Err(err).Msg("cannot abort stream")
+		return
+	}
+	_ = conn.Close()
 }
 
 // ResponseOK ...
@@ -46,6 +68,14 @@ func ResponseOK(eCtx echo.Context, data interface{}) error {
 	return eCtx.JSON(http.StatusOK, data)
 }
 
+// ResponseOKOrNoContent returns the data if there is any and no content otherwise
+func ResponseOKOrNoContent(eCtx echo.Context, data interface{}) error {
+	if data == nil {
+		return eCtx.NoContent(http.StatusNoContent)
+	}
+	return eCtx.JSON(http.StatusOK, data)
+}
+
 // ResponseCreated ...
 func ResponseCreated(eCtx echo.Context, data interface{}) error {
 	return eCtx.JSON(http.StatusCreated, data)
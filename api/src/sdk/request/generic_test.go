@@ -0,0 +1,68 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+)
+
+// failingReader returns its content then fails
+type failingReader struct {
+	io.Reader
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		return n, errors.New("storage failure")
+	}
+	return n, err
+}
+
+func (r *failingReader) Close() error { return nil }
+
+func TestResponseStream(t *testing.T) {
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(eCtx echo.Context) error {
+			l := zerolog.Nop()
+			eCtx.SetRequest(eCtx.Request().WithContext(logger.SetLogger(eCtx.Request().Context(), &l)))
+			return next(eCtx)
+		}
+	})
+	e.GET("/complete", func(eCtx echo.Context) error {
+		return ResponseStream(eCtx, ioutil.NopCloser(strings.NewReader("complete body")))
+	})
+	e.GET("/failing", func(eCtx echo.Context) error {
+		return ResponseStream(eCtx, &failingReader{strings.NewReader("partial body")})
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	t.Run("a complete stream is received", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/complete")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "complete body", string(body))
+	})
+	t.Run("a failing stream aborts the download", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/failing")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		defer resp.Body.Close()
+		_, err = ioutil.ReadAll(resp.Body)
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	})
+}
@@ -0,0 +1,139 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/logger"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+)
+
+// ByteRange is a satisfiable range of bytes of a resource
+type ByteRange struct {
+	Start  int64
+	Length int64
+}
+
+// ParseRange parses the value of a Range header for a resource of the given size.
+// Only single byte ranges are handled: a nil range is returned for an empty, malformed
+// or multiple ranges header, meaning the whole resource shall be sent.
+// An error is returned if the range cannot be satisfied.
+func ParseRange(header string, size int64) (*ByteRange, error) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return nil, nil
+	}
+	spec := strings.TrimSpace(strings.TrimPrefix(header, prefix))
+	if strings.Contains(spec, ",") {
+		return nil, nil
+	}
+	dash := strings.Index(spec, "-")
+	if dash < 0 {
+		return nil, nil
+	}
+	rawStart, rawEnd := strings.TrimSpace(spec[:dash]), strings.TrimSpace(spec[dash+1:])
+
+	unsatisfiable := unsatisfiableRange()
+
+	// suffix range: the last bytes of the resource
+	if rawStart == "" {
+		suffix, err := strconv.ParseInt(rawEnd, 10, 64)
+		if err != nil || suffix < 0 {
+			return nil, nil
+		}
+		if suffix == 0 {
+			return nil, unsatisfiable
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return &ByteRange{Start: size - suffix, Length: suffix}, nil
+	}
+
+	start, err := strconv.ParseInt(rawStart, 10, 64)
+	if err != nil || start < 0 {
+		return nil, nil
+	}
+	if start >= size {
+		return nil, unsatisfiable
+	}
+	// open range: from start to the end of the resource
+	end := size - 1
+	if rawEnd != "" {
+		end, err = strconv.ParseInt(rawEnd, 10, 64)
+		if err != nil || end < start {
+			return nil, nil
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return &ByteRange{Start: start, Length: end - start + 1}, nil
+}
+
+func unsatisfiableRange() error {
+	return merr.RequestedRangeNotSatisfiable().Ori(merr.OriHeaders).Add("range", merr.DVInvalid)
+}
+
+// MatchETag returns true if the value of an If-None-Match or If-Match header matches the etag
+func MatchETag(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RangedStream is a stream of a resource of a known size, potentially partial
+type RangedStream struct {
+	ReadCloser io.ReadCloser
+	// Size of the whole resource
+	Size int64
+	// Range of the resource contained in the stream - nil for the whole resource
+	Range *ByteRange
+	ETag  string
+	// NotModified is set when the client already has the resource - the stream is then nil
+	NotModified bool
+	// Unsatisfiable is set when the requested range cannot be served - the stream is then nil
+	Unsatisfiable bool
+}
+
+// ResponseRangedStream renders a RangedStream with the corresponding status and headers
+func ResponseRangedStream(eCtx echo.Context, data interface{}) error {
+	stream := data.(RangedStream)
+	header := eCtx.Response().Header()
+	header.Set("Accept-Ranges", "bytes")
+	if stream.ETag != "" {
+		header.Set("ETag", stream.ETag)
+	}
+	if stream.NotModified {
+		return eCtx.NoContent(http.StatusNotModified)
+	}
+	// the size is given so the client can request a valid range
+	if stream.Unsatisfiable {
+		header.Set("Content-Range", fmt.Sprintf("bytes */%d", stream.Size))
+		return unsatisfiableRange()
+	}
+
+	defer func(ctx context.Context) {
+		if err := stream.ReadCloser.Close(); err != nil {
+			logger.FromCtx(ctx).Error().Msgf("cannot close stream: %v", err)
+		}
+	}(eCtx.Request().Context())
+
+	if stream.Range == nil {
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(stream.Size, 10))
+		return eCtx.Stream(http.StatusOK, echo.MIMEOctetStream, stream.ReadCloser)
+	}
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(stream.Range.Length, 10))
+	header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", stream.Range.Start, stream.Range.Start+stream.Range.Length-1, stream.Size))
+	return eCtx.Stream(http.StatusPartialContent, echo.MIMEOctetStream, stream.ReadCloser)
+}
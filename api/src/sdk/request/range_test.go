@@ -0,0 +1,92 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := map[string]struct {
+		header        string
+		expectedRange *ByteRange
+		expectedErr   bool
+	}{
+		"empty header sends the whole resource": {
+			header: "",
+		},
+		"closed range": {
+			header:        "bytes=0-99",
+			expectedRange: &ByteRange{Start: 0, Length: 100},
+		},
+		"open range": {
+			header:        "bytes=900-",
+			expectedRange: &ByteRange{Start: 900, Length: 100},
+		},
+		"suffix range": {
+			header:        "bytes=-10",
+			expectedRange: &ByteRange{Start: 990, Length: 10},
+		},
+		"end after the size is truncated": {
+			header:        "bytes=500-5000",
+			expectedRange: &ByteRange{Start: 500, Length: 500},
+		},
+		"suffix bigger than the size is truncated": {
+			header:        "bytes=-5000",
+			expectedRange: &ByteRange{Start: 0, Length: 1000},
+		},
+		"multiple ranges are ignored": {
+			header: "bytes=0-10,20-30",
+		},
+		"malformed range is ignored": {
+			header: "bytes=ten-twenty",
+		},
+		"unknown unit is ignored": {
+			header: "items=0-10",
+		},
+		"start after the size is not satisfiable": {
+			header:      "bytes=1000-",
+			expectedErr: true,
+		},
+		"empty suffix is not satisfiable": {
+			header:      "bytes=-0",
+			expectedErr: true,
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			byteRange, err := ParseRange(test.header, 1000)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRange, byteRange)
+		})
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	assert.True(t, MatchETag(`"abc"`, `"abc"`))
+	assert.True(t, MatchETag(`"xyz", W/"abc"`, `"abc"`))
+	assert.True(t, MatchETag(`*`, `"abc"`))
+	assert.False(t, MatchETag(`"xyz"`, `"abc"`))
+	assert.False(t, MatchETag(``, `"abc"`))
+}
+
+func TestResponseRangedStreamUnsatisfiable(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	eCtx := echo.New().NewContext(req, rec)
+
+	err := ResponseRangedStream(eCtx, RangedStream{Size: 1000, ETag: `"abc"`, Unsatisfiable: true})
+	assert.Error(t, err)
+	assert.Equal(t, merr.RequestedRangeNotSatisfiableCode, merr.From(err).Co)
+	assert.Equal(t, "bytes */1000", rec.Header().Get("Content-Range"))
+	assert.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+}
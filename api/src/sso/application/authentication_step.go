@@ -9,6 +9,7 @@ import (
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/atomic"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
@@ -17,6 +18,7 @@ import (
 
 // AuthenticationStepCmd orders:
 // - the retry of an authentication step init for the identity
+// - the init of a usernameless webauthn step if no identity is set
 type AuthenticationStepCmd struct {
 	LoginChallenge string     `json:"login_challenge"`
 	Step           authn.Step `json:"authn_step"`
@@ -29,7 +31,7 @@ func (cmd *AuthenticationStepCmd) BindAndValidate(eCtx echo.Context) error {
 	}
 
 	if err := v.ValidateStruct(&cmd.Step,
-		v.Field(&cmd.Step.IdentityID, v.Required, is.UUIDv4.Error("identity_id must be an UUIDv4")),
+		v.Field(&cmd.Step.IdentityID, v.When(cmd.Step.MethodName != oidc.AMRWebauthn, v.Required), is.UUIDv4.Error("identity_id must be an UUIDv4")),
 		v.Field(&cmd.Step.MethodName, v.Required),
 	); err != nil {
 		return err
@@ -51,10 +53,15 @@ func (sso *SSOService) InitAuthnStep(ctx context.Context, genReq request.Request
 	}
 	defer atomic.SQLRollback(ctx, tr, &err)
 
-	// 0. check if the identity exists
-	curIdentity, err := identity.Get(ctx, tr, cmd.Step.IdentityID)
-	if err != nil {
-		return nil, err
+	// 0. check if the identity exists - no identity is set on usernameless steps
+	var curIdentity *identity.Identity
+	if cmd.Step.IdentityID != "" {
+		var existing identity.Identity
+		existing, err = identity.Get(ctx, tr, cmd.Step.IdentityID)
+		if err != nil {
+			return nil, err
+		}
+		curIdentity = &existing
 	}
 
 	// 1. check login challenge
@@ -64,9 +71,21 @@ func (sso *SSOService) InitAuthnStep(ctx context.Context, genReq request.Request
 	}
 
 	// 2. we try to init the authentication step
-	err = sso.AuthenticationService.InitStep(ctx, tr, sso.redConn, curIdentity, cmd.Step.MethodName)
+	step, err := sso.AuthenticationService.InitStep(ctx, tr, sso.redConn, curIdentity, cmd.Step.MethodName)
 	if err != nil {
 		return nil, err
 	}
-	return nil, tr.Commit()
+	if err = tr.Commit(); err != nil {
+		return nil, err
+	}
+
+	// 3. usernameless steps return their metadata to the end-user
+	if step == nil {
+		return nil, nil
+	}
+	view := nextStepView{MethodName: step.MethodName}
+	if step.RawJSONMetadata != nil {
+		view.Metadata = &step.RawJSONMetadata
+	}
+	return view, nil
 }
@@ -12,6 +12,7 @@ type Session struct {
 		Client struct {
 			ID string `json:"client_id"`
 		} `json:"client"`
+		LoginSessionID string `json:"login_session_id"`
 	} `json:"consent_request"`
 }
 
@@ -172,6 +172,15 @@ func (h *HydraHTTP) GetConsentSessions(ctx context.Context, identityID string) (
 	return consents, nil
 }
 
+// RevokeConsentSessions of a subject for a client, the tokens issued for the consents are also revoked
+func (h *HydraHTTP) RevokeConsentSessions(ctx context.Context, subject string, clientID string) error {
+	route := fmt.Sprintf(
+		"/oauth2/auth/sessions/consent?subject=%s&client=%s",
+		url.QueryEscape(subject), url.QueryEscape(clientID),
+	)
+	return h.adminFormRester.Delete(ctx, route, nil)
+}
+
 // UserInfo ...
 func (h *HydraHTTP) GetUserInfo(ctx context.Context, token string) (*userinfo.UserInfo, error) {
 	userInfo := userinfo.UserInfo{}
@@ -6,6 +6,7 @@ import (
 	"net/url"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/application/authflow/login"
 )
 
@@ -16,18 +17,20 @@ func (afs Service) GetLoginContext(ctx context.Context, loginChallenge string) (
 
 // BuildAndAcceptLogin takes the OIDCContext as the one used to login
 // It builds the acceptance object and sends it as accepted to the authorization server
-func (afs Service) BuildAndAcceptLogin(ctx context.Context, loginCtx login.Context) (string, error) {
+// The policy of the identity tells how long the login session is remembered
+func (afs Service) BuildAndAcceptLogin(ctx context.Context, loginCtx login.Context, policy oidc.Policy) (string, error) {
 	if len(loginCtx.OIDCContext.ACRValues()) == 0 {
 		return "", fmt.Errorf("acr values are empty")
 	}
 	acr := loginCtx.OIDCContext.ACRValues().Get()
+	rememberFor := policy.RememberFor(acr)
 	acceptance := login.Acceptance{
 		Subject: loginCtx.Subject,
 		ACR:     acr,
 		Context: loginCtx.OIDCContext,
 
-		Remember:    (acr.RememberFor() > 0),
-		RememberFor: acr.RememberFor(),
+		Remember:    (rememberFor > 0),
+		RememberFor: rememberFor,
 	}
 	return afs.authFlow.Login(ctx, loginCtx.Challenge, acceptance)
 }
@@ -42,6 +45,33 @@ func (afs Service) LoginRedirectErr(err error) string {
 	return buildRedirectErr(merr.InvalidFlowCode, err.Error(), afs.loginPageURL)
 }
 
+// BuildLoginStepURL to continue a login flow with an authn step already prepared for the identity
+func (afs Service) BuildLoginStepURL(loginChallenge, identityID string, methodName oidc.MethodRef) string {
+	finalURL := *afs.loginPageURL
+
+	query := url.Values{}
+	query.Set("login_challenge", loginChallenge)
+	query.Set("identity_id", identityID)
+	query.Set("method_name", string(methodName))
+
+	finalURL.RawQuery = query.Encode()
+	return finalURL.String()
+}
+
+// BuildMFAEnrollmentURL returns the login page url asking the end-user to enroll a MFA method
+// before continuing the login flow with an authn step using it
+func (afs Service) BuildMFAEnrollmentURL(loginChallenge, identityID string) string {
+	finalURL := *afs.loginPageURL
+
+	query := url.Values{}
+	query.Set("login_challenge", loginChallenge)
+	query.Set("identity_id", identityID)
+	query.Set("next", "mfa_enrollment")
+
+	finalURL.RawQuery = query.Encode()
+	return finalURL.String()
+}
+
 // BuildLoginURL ...
 func (afs Service) BuildLoginURL(loginChallenge string) string {
 	// build the login URL
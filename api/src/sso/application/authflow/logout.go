@@ -16,3 +16,31 @@ func (afs Service) Logout(ctx context.Context, subject string, token string) err
 	}
 	return nil
 }
+
+// RevokeLoginSessions of the subjects: their login sessions and all their consents are revoked,
+// and hydra revokes the tokens issued for them.
+// NOTE: hydra can only revoke login sessions per subject and consents per client,
+// so a single login session cannot be revoked without affecting the others.
+func (afs Service) RevokeLoginSessions(ctx context.Context, subjects []string) error {
+	for _, subject := range subjects {
+		if err := afs.authFlow.DeleteSession(ctx, subject); err != nil {
+			return merr.From(err).Desc("deleting login sessions")
+		}
+		sessions, err := afs.authFlow.GetConsentSessions(ctx, subject)
+		if err != nil {
+			return merr.From(err).Desc("getting consent sessions")
+		}
+		revoked := map[string]bool{}
+		for _, session := range sessions {
+			clientID := session.ConsentRequest.Client.ID
+			if revoked[clientID] {
+				continue
+			}
+			if err := afs.authFlow.RevokeConsentSessions(ctx, subject, clientID); err != nil {
+				return merr.From(err).Desc("revoking consent sessions")
+			}
+			revoked[clientID] = true
+		}
+	}
+	return nil
+}
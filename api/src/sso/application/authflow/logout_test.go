@@ -0,0 +1,61 @@
+package authflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/application/authflow/consent"
+)
+
+// consentsRepo only implements the login and consent sessions methods of the authFlowRepo
+type consentsRepo struct {
+	authFlowRepo
+	sessions        map[string][]consent.Session
+	deletedSubjects []string
+	revoked         []string
+}
+
+func (r *consentsRepo) DeleteSession(_ context.Context, subject string) error {
+	r.deletedSubjects = append(r.deletedSubjects, subject)
+	return nil
+}
+
+func (r *consentsRepo) GetConsentSessions(_ context.Context, subject string) ([]consent.Session, error) {
+	return r.sessions[subject], nil
+}
+
+func (r *consentsRepo) RevokeConsentSessions(_ context.Context, subject string, clientID string) error {
+	r.revoked = append(r.revoked, subject+"/"+clientID)
+	return nil
+}
+
+func newConsentSession(clientID, loginSessionID string) consent.Session {
+	session := consent.Session{}
+	session.ConsentRequest.Client.ID = clientID
+	session.ConsentRequest.LoginSessionID = loginSessionID
+	return session
+}
+
+func TestRevokeLoginSessions(t *testing.T) {
+	repo := &consentsRepo{
+		sessions: map[string][]consent.Session{
+			"identity": {
+				newConsentSession("self", "lost-laptop"),
+				newConsentSession("self", "phone"),
+				newConsentSession("third-party", "phone"),
+			},
+			"account": {
+				newConsentSession("third-party", "lost-laptop"),
+			},
+		},
+	}
+	afs := Service{authFlow: repo}
+
+	err := afs.RevokeLoginSessions(context.Background(), []string{"identity", "account"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"identity", "account"}, repo.deletedSubjects)
+	// consents are revoked once per client
+	assert.Equal(t, []string{"identity/self", "identity/third-party", "account/third-party"}, repo.revoked)
+}
@@ -16,11 +16,16 @@ import (
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/application/authflow/consent"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/identity"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/org"
 )
 
 // ConsentInitCmd ...
 type ConsentInitCmd struct {
 	ConsentChallenge string `query:"consent_challenge"`
+
+	// device of the end-user recorded on the authn session
+	userAgent string
+	ip        string
 }
 
 // BindAndValidate ...
@@ -28,6 +33,8 @@ func (cmd *ConsentInitCmd) BindAndValidate(eCtx echo.Context) error {
 	if err := eCtx.Bind(cmd); err != nil {
 		return merr.BadRequest().Ori(merr.OriQuery).Desc(err.Error())
 	}
+	cmd.userAgent = eCtx.Request().UserAgent()
+	cmd.ip = eCtx.RealIP()
 
 	return v.ValidateStruct(cmd,
 		v.Field(&cmd.ConsentChallenge, v.Required),
@@ -59,12 +66,19 @@ func (sso *SSOService) InitConsent(ctx context.Context, gen request.Request) (in
 
 	// 3. upsert the Sec Level authentication session
 	// this is the first time we receive a potentially new login session id
+	// its lifetime depends on the policy of the identity
+	policy, err := org.GetAuthnPolicy(ctx, sso.ssoDB, curIdentity.ID)
+	if err != nil {
+		return sso.authFlowService.ConsentRedirectErr(err), nil
+	}
 	session := authn.Session{
 		ID:          consentCtx.LoginSessionID,
 		ACR:         consentCtx.ACR,
-		RememberFor: consentCtx.ACR.RememberFor(),
+		RememberFor: policy.RememberFor(consentCtx.ACR),
 		IdentityID:  consentCtx.OIDCContext.MID(),
 		AccountID:   consentCtx.OIDCContext.AID(),
+		UserAgent:   cmd.userAgent,
+		IPAddress:   cmd.ip,
 	}
 	if err := sso.AuthenticationService.UpsertSession(ctx, session); err != nil {
 		return sso.authFlowService.ConsentRedirectErr(err), nil
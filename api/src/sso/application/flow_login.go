@@ -2,22 +2,28 @@ package application
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	v "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/labstack/echo/v4"
 	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/types"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/atomic"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/authz"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/application/authflow"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/application/authflow/login"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/federation"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/identity"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/org"
 )
 
 // LoginInitCmd ...
@@ -57,14 +63,19 @@ func (sso *SSOService) LoginInit(ctx context.Context, gen request.Request) (inte
 		session, err := sso.AuthenticationService.GetSession(ctx, loginCtx.SessionID)
 		if err == nil {
 			sessionACR = session.ACR
+			// the organizations of the identity might require a higher ACR than the session one
+			policy, err := org.GetAuthnPolicy(ctx, sso.ssoDB, session.IdentityID)
+			if err != nil {
+				return sso.authFlowService.LoginRedirectErr(merr.From(err).Desc("getting authn policy")), nil
+			}
 			// if the session ACR is higher or equivalent to the expected ACR, we accept the login
-			if session.ACR >= expectedACR {
+			if session.ACR >= expectedACR && !session.ACR.LessThan(policy.MinACR) {
 				// set browser cookie as authentication method
 				loginCtx.OIDCContext.AddAMR(oidc.AMRBrowserCookie)
 				loginCtx.OIDCContext.SetACRValue(session.ACR)
 				loginCtx.OIDCContext.SetMID(session.IdentityID)
 				loginCtx.OIDCContext.SetAID(session.AccountID)
-				redirectTo, err := sso.authFlowService.BuildAndAcceptLogin(ctx, loginCtx)
+				redirectTo, err := sso.authFlowService.BuildAndAcceptLogin(ctx, loginCtx, policy)
 				if err != nil {
 					return sso.authFlowService.LoginRedirectErr(err), nil
 				}
@@ -158,17 +169,9 @@ func (sso *SSOService) RequireIdentity(ctx context.Context, gen request.Request)
 	}
 
 	// 3. compute the expected ACR
-	// if no ACR is expected, set it according to the identity state
-	expectedACR := logCtx.OIDCContext.ACRValues().Get()
-	if expectedACR == oidc.ACR0 {
-		expectedACR = oidc.ACR1
-		if curIdentity.AccountID.Valid {
-			expectedACR = oidc.ACR2
-		}
-	}
-	// in all cases, if any MFA method is setup, the expected ACR is enforce according to it
-	if curIdentity.MFAMethod != "disabled" {
-		expectedACR = oidc.GetMethodACR(curIdentity.MFAMethod)
+	expectedACR, err := sso.computeExpectedACR(ctx, tr, logCtx, curIdentity)
+	if err != nil {
+		return nil, err
 	}
 
 	// 4. get the appropriate authn step - this is the start of the login flow so the current ACR is 0
@@ -208,6 +211,47 @@ func (sso *SSOService) RequireIdentity(ctx context.Context, gen request.Request)
 	return view, nil
 }
 
+// computeExpectedACR of the login flow for the identity
+func (sso *SSOService) computeExpectedACR(
+	ctx context.Context, exec boil.ContextExecutor,
+	logCtx login.Context, curIdentity identity.Identity,
+) (oidc.ClassRef, error) {
+	// if no ACR is expected, set it according to the identity state
+	expectedACR := logCtx.OIDCContext.ACRValues().Get()
+	if expectedACR == oidc.ACR0 {
+		expectedACR = oidc.ACR1
+		if curIdentity.AccountID.Valid {
+			expectedACR = oidc.ACR2
+		}
+	}
+	// in all cases, if any MFA method is setup, the expected ACR is enforce according to it
+	if curIdentity.MFAMethod != "disabled" {
+		expectedACR = oidc.GetMethodACR(curIdentity.MFAMethod)
+	}
+	// the organizations of the identity might require a higher ACR
+	// without the required MFA method, the authn process ends at the reachable ACR
+	// and the login is only accepted once a MFA method is enrolled then used
+	policy, err := org.GetAuthnPolicy(ctx, exec, curIdentity.ID)
+	if err != nil {
+		return expectedACR, merr.From(err).Desc("getting authn policy")
+	}
+	if expectedACR.LessThan(policy.MinACR) {
+		expectedACR = policy.MinACR
+		if reachable := reachableACR(curIdentity); reachable.LessThan(policy.MinACR) {
+			expectedACR = reachable
+		}
+	}
+	return expectedACR, nil
+}
+
+// reachableACR by the identity considering its MFA method
+func reachableACR(curIdentity identity.Identity) oidc.ClassRef {
+	if curIdentity.MFAMethod != "disabled" {
+		return oidc.GetMethodACR(curIdentity.MFAMethod)
+	}
+	return oidc.ACR2
+}
+
 // LoginInfoQuery ...
 type LoginInfoQuery struct {
 	Challenge string `query:"login_challenge"`
@@ -264,6 +308,9 @@ func (sso *SSOService) LoginInfo(ctx context.Context, gen request.Request) (inte
 type LoginAuthnStepCmd struct {
 	LoginChallenge string     `json:"login_challenge"`
 	Step           authn.Step `json:"authn_step"`
+
+	// ip of the end-user used to limit failed attempts
+	ip string
 }
 
 // BindAndValidate ...
@@ -271,10 +318,12 @@ func (cmd *LoginAuthnStepCmd) BindAndValidate(eCtx echo.Context) error {
 	if err := eCtx.Bind(cmd); err != nil {
 		return merr.BadRequest().Ori(merr.OriBody).Desc(err.Error())
 	}
+	cmd.ip = eCtx.RealIP()
 
 	// validate nested structure separately
+	// the identity of usernameless webauthn steps is found using the assertion
 	if err := v.ValidateStruct(&cmd.Step,
-		v.Field(&cmd.Step.IdentityID, v.Required, is.UUIDv4),
+		v.Field(&cmd.Step.IdentityID, v.When(cmd.Step.MethodName != oidc.AMRWebauthn, v.Required), is.UUIDv4),
 		v.Field(&cmd.Step.MethodName, v.Required),
 		v.Field(&cmd.Step.RawJSONMetadata, v.Required),
 	); err != nil {
@@ -323,17 +372,32 @@ func (sso *SSOService) AssertAuthnStep(ctx context.Context, gen request.Request)
 	if err != nil {
 		return view, err
 	}
+	// retrieve the identity of usernameless steps from the discoverable credential
+	if cmd.Step.IdentityID == "" {
+		cmd.Step.IdentityID, err = authn.UsernamelessIdentityID(cmd.Step)
+		if err != nil {
+			return view, err
+		}
+		if err = v.Validate(cmd.Step.IdentityID, is.UUIDv4); err != nil {
+			return view, merr.Forbidden().Ori(merr.OriBody).Desc("invalid user handle").Add("metadata", merr.DVInvalid)
+		}
+	}
 	curIdentity, err := identity.Get(ctx, tr, cmd.Step.IdentityID)
 	if err != nil {
 		return view, err
 	}
 
 	// try to assert the authentication step
-	err = sso.AuthenticationService.AssertStep(ctx, tr, sso.redConn, logCtx.Challenge, &curIdentity, cmd.Step)
+	err = sso.AuthenticationService.AssertStep(ctx, tr, sso.redConn, logCtx.Challenge, cmd.ip, &curIdentity, cmd.Step)
 	if err != nil {
 		return view, err
 	}
 
+	// a MFA method enrolled during the login flow becomes the MFA method of the identity once used
+	if err = sso.adoptEnrolledMFAMethod(ctx, tr, &curIdentity, cmd.Step.MethodName); err != nil {
+		return view, err
+	}
+
 	// upgrade the authentication process
 	process, err := sso.AuthenticationService.UpgradeProcess(ctx, tr, sso.redConn, logCtx.Challenge, curIdentity, cmd.Step.MethodName)
 	if err != nil {
@@ -361,21 +425,297 @@ func (sso *SSOService) AssertAuthnStep(ctx context.Context, gen request.Request)
 	}
 
 	// finally accept the login!
+	var redirectTo string
+	var mfaEnrollmentRequired bool
+	redirectTo, mfaEnrollmentRequired, err = sso.acceptLogin(ctx, logCtx, curIdentity, process.CompleteAMRs)
+	if err != nil {
+		return view, err
+	}
+	// the login is accepted once the end-user has enrolled a MFA method using the process access token
+	// then performed an authn step with it
+	if mfaEnrollmentRequired {
+		view.Next = "mfa_enrollment"
+		return view, nil
+	}
+	view.Next = "redirect"
+	view.RedirectTo = &redirectTo
+	return view, nil
+}
+
+// adoptEnrolledMFAMethod sets the asserted method as MFA method of the identity
+// if the identity has none while its organizations require one to reach their minimum ACR
+func (sso *SSOService) adoptEnrolledMFAMethod(
+	ctx context.Context, exec boil.ContextExecutor,
+	curIdentity *identity.Identity, methodName oidc.MethodRef,
+) error {
+	if curIdentity.MFAMethod != "disabled" || (methodName != oidc.AMRTOTP && methodName != oidc.AMRWebauthn) {
+		return nil
+	}
+	policy, err := org.GetAuthnPolicy(ctx, exec, curIdentity.ID)
+	if err != nil {
+		return merr.From(err).Desc("getting authn policy")
+	}
+	if !reachableACR(*curIdentity).LessThan(policy.MinACR) {
+		return nil
+	}
+	curIdentity.MFAMethod = string(methodName)
+	if err := identity.Update(ctx, exec, curIdentity); err != nil {
+		return merr.From(err).Desc("setting mfa method")
+	}
+	return nil
+}
 
+// acceptLogin of the identity once the authn process is complete, it returns the redirect url.
+// The login is never accepted below the minimum ACR required by the organizations of the identity:
+// true is returned instead if the identity must enroll a MFA method to reach it.
+func (sso *SSOService) acceptLogin(
+	ctx context.Context,
+	logCtx login.Context, curIdentity identity.Identity, amrs oidc.MethodRefs,
+) (string, bool, error) {
 	// set subject to the account id if there otherwise use the identity id
 	if curIdentity.AccountID.Valid {
 		logCtx.Subject = curIdentity.AccountID.String
 	} else {
 		logCtx.Subject = curIdentity.ID
 	}
-	logCtx.OIDCContext.SetACRValue(process.CompleteAMRs.ToACR())
-	logCtx.OIDCContext.SetAMRs(process.CompleteAMRs)
+	logCtx.OIDCContext.SetACRValue(amrs.ToACR())
+	logCtx.OIDCContext.SetAMRs(amrs)
 	logCtx.OIDCContext.SetMID(curIdentity.ID)
 	logCtx.OIDCContext.SetAID(curIdentity.AccountID)
 
-	view.Next = "redirect"
-	var redirectTo string
-	redirectTo, err = sso.authFlowService.BuildAndAcceptLogin(ctx, logCtx)
-	view.RedirectTo = &redirectTo
-	return view, err
+	policy, err := org.GetAuthnPolicy(ctx, sso.ssoDB, curIdentity.ID)
+	if err != nil {
+		return "", false, merr.From(err).Desc("getting authn policy")
+	}
+	if acr := amrs.ToACR(); acr.LessThan(policy.MinACR) {
+		if reachableACR(curIdentity).LessThan(policy.MinACR) {
+			return "", true, nil
+		}
+		return "", false, merr.Forbidden().Descf("acr %s is below the acr %s required by the organizations", acr, policy.MinACR)
+	}
+	redirectTo, err := sso.authFlowService.BuildAndAcceptLogin(ctx, logCtx, policy)
+	if err != nil {
+		return "", false, err
+	}
+	return redirectTo, false, nil
+}
+
+// federationCookie binds the state of a federated login to the browser
+const federationCookie = "federationstate"
+
+// FederatedLoginQuery ...
+type FederatedLoginQuery struct {
+	provider       string
+	LoginChallenge string `query:"login_challenge"`
+}
+
+// BindAndValidate ...
+func (query *FederatedLoginQuery) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(query); err != nil {
+		return merr.BadRequest().Ori(merr.OriQuery)
+	}
+	query.provider = eCtx.Param("provider")
+	if query.LoginChallenge == "" {
+		return merr.BadRequest().Ori(merr.OriQuery).Add("login_challenge", merr.DVRequired)
+	}
+	return nil
+}
+
+// FederatedLoginView ...
+type FederatedLoginView struct {
+	RedirectTo string
+
+	// used to bind the state to the browser
+	ForCookies struct {
+		StateBinding   string
+		ExpirationDate time.Time
+	}
+}
+
+// FederatedLogin redirects the end-user to an upstream provider to authenticate
+// the provider redirects back the end-user to the federated login callback
+func (sso *SSOService) FederatedLogin(ctx context.Context, gen request.Request) (interface{}, error) {
+	query := gen.(*FederatedLoginQuery)
+	view := FederatedLoginView{}
+
+	// ensure the login challenge is correct
+	logCtx, err := sso.authFlowService.GetLoginContext(ctx, query.LoginChallenge)
+	if err != nil {
+		view.RedirectTo = sso.authFlowService.LoginRedirectErr(err)
+		return view, nil
+	}
+	authURL, binding, err := sso.federationService.BuildAuthURL(ctx, query.provider, logCtx.Challenge)
+	if err != nil {
+		view.RedirectTo = sso.authFlowService.LoginRedirectErr(err)
+		return view, nil
+	}
+	view.RedirectTo = authURL
+	view.ForCookies.StateBinding = binding
+	view.ForCookies.ExpirationDate = time.Now().Add(federation.StateLifetime())
+	return view, nil
+}
+
+// SetFederationCookie binding the state of the federated login to the browser.
+// The cookie is sent back on the redirection from the upstream provider: it cannot be strict.
+func (sso *SSOService) SetFederationCookie(eCtx echo.Context, data interface{}) error {
+	view, ok := data.(FederatedLoginView)
+	if !ok {
+		return merr.Internal().Desc("expect application.FederatedLoginView type")
+	}
+	if view.ForCookies.StateBinding == "" {
+		return nil
+	}
+	eCtx.SetCookie(&http.Cookie{
+		Name:     federationCookie,
+		Value:    view.ForCookies.StateBinding,
+		Expires:  view.ForCookies.ExpirationDate,
+		HttpOnly: true, Secure: true,
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+	})
+	return nil
+}
+
+// CleanFederationCookie once the state has been consumed
+func (sso *SSOService) CleanFederationCookie(eCtx echo.Context, _ interface{}) error {
+	authz.DelCookies(eCtx, federationCookie)
+	return nil
+}
+
+// FederatedCallbackQuery received from the upstream provider
+type FederatedCallbackQuery struct {
+	State            string `query:"state"`
+	Code             string `query:"code"`
+	Error            string `query:"error"`
+	ErrorDescription string `query:"error_description"`
+
+	// binding of the state set in the browser by the federated login
+	stateBinding string
+}
+
+// BindAndValidate ...
+func (query *FederatedCallbackQuery) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(query); err != nil {
+		return merr.BadRequest().Ori(merr.OriQuery)
+	}
+	// a missing cookie is rejected when the state is consumed
+	if cookie, err := eCtx.Cookie(federationCookie); err == nil {
+		query.stateBinding = cookie.Value
+	}
+	if err := v.ValidateStruct(query,
+		v.Field(&query.State, v.Required),
+		v.Field(&query.Code, v.When(query.Error == "", v.Required)),
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FederatedCallbackView ...
+type FederatedCallbackView struct {
+	RedirectTo string
+
+	// used to set the process access token cookies when a MFA method must be enrolled
+	ForCookies struct {
+		AccessToken    string
+		ExpirationDate time.Time
+	}
+}
+
+// FederatedCallback ends the authentication on the upstream provider.
+// The email returned by the provider is mapped to an identity which is added to the authn process:
+// - the end-user is redirected to the login page if more authn steps are required
+// - the end-user is redirected to the login page if a MFA method must be enrolled
+// - the login flow is accepted otherwise
+// Errors are returned to the login page.
+func (sso *SSOService) FederatedCallback(ctx context.Context, gen request.Request) (interface{}, error) {
+	query := gen.(*FederatedCallbackQuery)
+
+	view, err := sso.federatedCallback(ctx, query)
+	if err != nil {
+		return FederatedCallbackView{RedirectTo: sso.authFlowService.LoginRedirectErr(err)}, nil
+	}
+	return view, nil
+}
+
+func (sso *SSOService) federatedCallback(ctx context.Context, query *FederatedCallbackQuery) (FederatedCallbackView, error) {
+	view := FederatedCallbackView{}
+
+	state, err := sso.federationService.ConsumeState(ctx, query.State, query.stateBinding)
+	if err != nil {
+		return view, err
+	}
+	if query.Error != "" {
+		return view, merr.Forbidden().Descf("upstream provider: %s %s", query.Error, query.ErrorDescription)
+	}
+	email, err := sso.federationService.Email(ctx, state, query.Code)
+	if err != nil {
+		return view, err
+	}
+
+	// start transaction since write actions will be performed
+	tr, err := sso.ssoDB.BeginTx(ctx, nil)
+	if err != nil {
+		return view, err
+	}
+	defer atomic.SQLRollback(ctx, tr, &err)
+
+	logCtx, err := sso.authFlowService.GetLoginContext(ctx, state.LoginChallenge)
+	if err != nil {
+		return view, err
+	}
+	curIdentity, err := identity.Require(ctx, tr, sso.redConn, email)
+	if err != nil {
+		return view, err
+	}
+
+	// the authentication by the provider is a step of the authn process
+	expectedACR, err := sso.computeExpectedACR(ctx, tr, logCtx, curIdentity)
+	if err != nil {
+		return view, err
+	}
+	if err = sso.AuthenticationService.UpdateProcess(ctx, sso.redConn, logCtx.Challenge, expectedACR, false); err != nil {
+		return view, merr.From(err).Desc("updating process")
+	}
+	process, err := sso.AuthenticationService.UpgradeProcess(ctx, tr, sso.redConn, logCtx.Challenge, curIdentity, oidc.AMRFederatedOIDC)
+	if err != nil {
+		return view, merr.From(err).Desc("upgrading authn process")
+	}
+	if cErr := tr.Commit(); cErr != nil {
+		return view, merr.From(cErr).Desc("committing transaction")
+	}
+
+	// the login page continues the login flow with the next authn step
+	if process.NextStep != nil {
+		view.RedirectTo = sso.authFlowService.BuildLoginStepURL(logCtx.Challenge, process.NextStep.IdentityID, process.NextStep.MethodName)
+		return view, nil
+	}
+	redirectTo, mfaEnrollmentRequired, err := sso.acceptLogin(ctx, logCtx, curIdentity, process.CompleteAMRs)
+	if err != nil {
+		return view, err
+	}
+	// the login page asks the end-user to enroll a MFA method using the process access token
+	// then continues the login flow with an authn step using it
+	if mfaEnrollmentRequired {
+		view.RedirectTo = sso.authFlowService.BuildMFAEnrollmentURL(logCtx.Challenge, curIdentity.ID)
+		view.ForCookies.AccessToken = process.AccessToken
+		view.ForCookies.ExpirationDate = time.Unix(process.ExpiresAt, 0)
+		return view, nil
+	}
+	view.RedirectTo = redirectTo
+	return view, nil
+}
+
+// SetFederatedAuthnCookie with the process access token allowing to enroll a MFA method during the login flow
+func (sso *SSOService) SetFederatedAuthnCookie(eCtx echo.Context, data interface{}) error {
+	view, ok := data.(FederatedCallbackView)
+	if !ok {
+		return merr.Internal().Desc("expect application.FederatedCallbackView type")
+	}
+	if view.ForCookies.AccessToken == "" {
+		return nil
+	}
+	authz.SetCookie(eCtx, "authnaccesstoken", view.ForCookies.AccessToken, view.ForCookies.ExpirationDate)
+	authz.SetCookie(eCtx, "authntokentype", "bearer", view.ForCookies.ExpirationDate)
+	return nil
 }
@@ -233,6 +233,7 @@ type UploadAvatarCmd struct {
 	identityID string
 	Data       io.Reader
 	Extension  string
+	Size       int64
 }
 
 // BindAndValidate ...
@@ -243,10 +244,6 @@ func (cmd *UploadAvatarCmd) BindAndValidate(eCtx echo.Context) error {
 	if err != nil {
 		return merr.BadRequest().Ori(merr.OriBody).Add("avatar", merr.DVRequired).Desc(err.Error())
 	}
-	if file.Size >= 3*1024*1024 {
-		return merr.BadRequest().Ori(merr.OriBody).Add("size", merr.DVInvalid).Desc("size must be < 3 mo")
-	}
-
 	data, err := file.Open()
 	if err != nil {
 		return err
@@ -254,6 +251,7 @@ func (cmd *UploadAvatarCmd) BindAndValidate(eCtx echo.Context) error {
 
 	cmd.Data = data
 	cmd.Extension = filepath.Ext(file.Filename)
+	cmd.Size = file.Size
 
 	return v.ValidateStruct(cmd,
 		v.Field(&cmd.identityID, v.Required, is.UUIDv4),
@@ -271,6 +269,11 @@ func (sso *SSOService) UploadAvatar(ctx context.Context, gen request.Request) (i
 		return nil, merr.Forbidden()
 	}
 
+	if cmd.Size > sso.uploadLimits.Avatar {
+		return nil, merr.BadRequest().Ori(merr.OriBody).Add("size", merr.DVInvalid).
+			Descf("the maximum avatar size is %d bytes", sso.uploadLimits.Avatar)
+	}
+
 	// start transaction since write actions will be performed
 	tr, err := sso.ssoDB.BeginTx(ctx, nil)
 	if err != nil {
@@ -0,0 +1,173 @@
+package application
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/labstack/echo/v4"
+	"github.com/volatiletech/null/v8"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/request"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/identity"
+)
+
+// session ids are generated by hydra
+var sessionIDFormat = regexp.MustCompile("^[a-zA-Z0-9-]+$")
+
+// IdentitySessionsQuery ...
+type IdentitySessionsQuery struct {
+	identityID string
+}
+
+// BindAndValidate ...
+func (query *IdentitySessionsQuery) BindAndValidate(eCtx echo.Context) error {
+	query.identityID = eCtx.Param("id")
+
+	if err := v.ValidateStruct(query,
+		v.Field(&query.identityID, v.Required, is.UUIDv4),
+	); err != nil {
+		return merr.From(err).Desc("validating identity sessions query")
+	}
+	return nil
+}
+
+// SessionView ...
+type SessionView struct {
+	ID         string        `json:"id"`
+	ACR        oidc.ClassRef `json:"acr"`
+	UserAgent  string        `json:"user_agent"`
+	IPAddress  string        `json:"ip_address"`
+	CreatedAt  null.Time     `json:"created_at"`
+	LastUsedAt null.Time     `json:"last_used_at"`
+	ExpiresAt  null.Time     `json:"expires_at"`
+}
+
+func newSessionView(session authn.Session) SessionView {
+	// sessions created before their use was recorded have no dates
+	return SessionView{
+		ID:         session.ID,
+		ACR:        session.ACR,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  null.NewTime(session.CreatedAt, !session.CreatedAt.IsZero()),
+		LastUsedAt: null.NewTime(session.LastUsedAt, !session.LastUsedAt.IsZero()),
+		ExpiresAt:  null.NewTime(session.ExpiresAt, !session.ExpiresAt.IsZero()),
+	}
+}
+
+// ListIdentitySessions the identity is logged in with, the most recently used first
+func (sso *SSOService) ListIdentitySessions(ctx context.Context, gen request.Request) (interface{}, error) {
+	query := gen.(*IdentitySessionsQuery)
+
+	// verify identity access
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil || acc.IdentityID != query.identityID {
+		return nil, merr.Forbidden()
+	}
+
+	sessions, err := sso.AuthenticationService.ListSessions(ctx, query.identityID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing sessions")
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	views := make([]SessionView, len(sessions))
+	for i, session := range sessions {
+		views[i] = newSessionView(session)
+	}
+	return views, nil
+}
+
+// IdentitySessionCmd ...
+type IdentitySessionCmd struct {
+	identityID string
+	sessionID  string
+}
+
+// BindAndValidate ...
+func (cmd *IdentitySessionCmd) BindAndValidate(eCtx echo.Context) error {
+	cmd.identityID = eCtx.Param("id")
+	cmd.sessionID = eCtx.Param("session-id")
+
+	if err := v.ValidateStruct(cmd,
+		v.Field(&cmd.identityID, v.Required, is.UUIDv4),
+		v.Field(&cmd.sessionID, v.Required, v.Match(sessionIDFormat)),
+	); err != nil {
+		return merr.From(err).Desc("validating identity session command")
+	}
+	return nil
+}
+
+// RevokeIdentitySession so the device using it cannot skip the login flow anymore and must login again.
+// Only the authn session is deleted: hydra only revokes the login sessions of a subject all at once,
+// so the tokens already issued to the device stay valid until they expire.
+func (sso *SSOService) RevokeIdentitySession(ctx context.Context, gen request.Request) (interface{}, error) {
+	cmd := gen.(*IdentitySessionCmd)
+
+	// verify identity access
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil || acc.IdentityID != cmd.identityID {
+		return nil, merr.Forbidden()
+	}
+
+	session, err := sso.AuthenticationService.GetSession(ctx, cmd.sessionID)
+	if err != nil {
+		return nil, merr.From(err).Desc("getting session")
+	}
+	if session.IdentityID != cmd.identityID {
+		return nil, merr.NotFound().Desc("session not found").Add("session_id", merr.DVNotFound)
+	}
+	if err := sso.AuthenticationService.DeleteSession(ctx, session); err != nil {
+		return nil, merr.From(err).Desc("deleting session")
+	}
+	return nil, nil
+}
+
+// RevokeIdentitySessions so all the devices of the identity must login again.
+// The login sessions and consents are revoked on hydra, which does it for a subject all at once,
+// so the tokens issued to all the devices are revoked too.
+func (sso *SSOService) RevokeIdentitySessions(ctx context.Context, gen request.Request) (interface{}, error) {
+	query := gen.(*IdentitySessionsQuery)
+
+	// verify identity access
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil || acc.IdentityID != query.identityID {
+		return nil, merr.Forbidden()
+	}
+
+	sessions, err := sso.AuthenticationService.ListSessions(ctx, query.identityID)
+	if err != nil {
+		return nil, merr.From(err).Desc("listing sessions")
+	}
+	return nil, sso.revokeSessions(ctx, query.identityID, sessions)
+}
+
+// revokeSessions on hydra then on the authn sessions storage,
+// so a failure on hydra can be retried
+func (sso *SSOService) revokeSessions(ctx context.Context, identityID string, sessions []authn.Session) error {
+	curIdentity, err := identity.Get(ctx, sso.ssoDB, identityID)
+	if err != nil {
+		return merr.From(err).Desc("getting identity")
+	}
+	// the subject of the consents is the account id if the identity has one
+	subjects := []string{curIdentity.ID}
+	if curIdentity.AccountID.Valid {
+		subjects = append(subjects, curIdentity.AccountID.String)
+	}
+	if err := sso.authFlowService.RevokeLoginSessions(ctx, subjects); err != nil {
+		return merr.From(err).Desc("revoking login sessions")
+	}
+	for _, session := range sessions {
+		if err := sso.AuthenticationService.DeleteSession(ctx, session); err != nil {
+			return merr.From(err).Desc("deleting session")
+		}
+	}
+	return nil
+}
@@ -241,3 +241,83 @@ func (sso *SSOService) GenerateSecret(ctx context.Context, genReq request.Reques
 	// bind and return view
 	return SecretView{secret}, nil
 }
+
+// SetOrgAuthnPolicyCmd ...
+// A null authn_policy removes the policy of the organization.
+type SetOrgAuthnPolicyCmd struct {
+	orgID       string
+	AuthnPolicy *oidc.PolicyRestriction `json:"authn_policy"`
+}
+
+// BindAndValidate ...
+func (cmd *SetOrgAuthnPolicyCmd) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(cmd); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	cmd.orgID = eCtx.Param("id")
+	if err := v.ValidateStruct(cmd,
+		v.Field(&cmd.orgID, v.Required, is.UUIDv4),
+	); err != nil {
+		return err
+	}
+	if cmd.AuthnPolicy != nil {
+		if err := cmd.AuthnPolicy.Validate(); err != nil {
+			return merr.BadRequest().Ori(merr.OriBody).Desc(err.Error()).Add("authn_policy", merr.DVInvalid)
+		}
+	}
+	return nil
+}
+
+// SetOrgAuthnPolicy restricting the authentication of the members of the organization. Requires admin accesses.
+func (sso *SSOService) SetOrgAuthnPolicy(ctx context.Context, genReq request.Request) (interface{}, error) {
+	cmd := genReq.(*SetOrgAuthnPolicyCmd)
+
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Forbidden()
+	}
+	if err := org.MustBeAdmin(ctx, sso.ssoDB, cmd.orgID, acc.IdentityID); err != nil {
+		return nil, merr.From(err).Desc("must be admin of the org")
+	}
+	return nil, org.UpdateAuthnPolicy(ctx, sso.ssoDB, cmd.orgID, cmd.AuthnPolicy)
+}
+
+// SetOrgMaxFileSizeCmd ...
+// A null max_file_size removes the override of the organization.
+type SetOrgMaxFileSizeCmd struct {
+	orgID       string
+	MaxFileSize null.Int64 `json:"max_file_size"`
+}
+
+// BindAndValidate ...
+func (cmd *SetOrgMaxFileSizeCmd) BindAndValidate(eCtx echo.Context) error {
+	if err := eCtx.Bind(cmd); err != nil {
+		return merr.From(err).Ori(merr.OriBody)
+	}
+	cmd.orgID = eCtx.Param("id")
+	return v.ValidateStruct(cmd,
+		v.Field(&cmd.orgID, v.Required, is.UUIDv4),
+		v.Field(&cmd.MaxFileSize, v.When(cmd.MaxFileSize.Valid, v.By(func(interface{}) error {
+			return v.Validate(cmd.MaxFileSize.Int64, v.Min(int64(1)))
+		}))),
+	)
+}
+
+// SetOrgMaxFileSize overriding the configured maximum size of files. Requires admin accesses.
+// The override cannot exceed the hard maximum configured by the instance.
+func (sso *SSOService) SetOrgMaxFileSize(ctx context.Context, genReq request.Request) (interface{}, error) {
+	cmd := genReq.(*SetOrgMaxFileSizeCmd)
+
+	acc := oidc.GetAccesses(ctx)
+	if acc == nil {
+		return nil, merr.Forbidden()
+	}
+	if err := org.MustBeAdmin(ctx, sso.ssoDB, cmd.orgID, acc.IdentityID); err != nil {
+		return nil, merr.From(err).Desc("must be admin of the org")
+	}
+	if cmd.MaxFileSize.Valid && cmd.MaxFileSize.Int64 > sso.uploadLimits.OrgMaxFileSize {
+		return nil, merr.BadRequest().Ori(merr.OriBody).Add("max_file_size", merr.DVInvalid).
+			Descf("the maximum file size cannot exceed %d bytes", sso.uploadLimits.OrgMaxFileSize)
+	}
+	return nil, org.UpdateMaxFileSize(ctx, sso.ssoDB, cmd.orgID, cmd.MaxFileSize)
+}
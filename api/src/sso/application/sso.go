@@ -6,9 +6,12 @@ import (
 
 	"github.com/go-redis/redis/v7"
 
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
+
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/application/authflow"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/crypto"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/federation"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/identity"
 )
 
@@ -17,9 +20,11 @@ type SSOService struct {
 	identityService            identity.Service
 	authFlowService            authflow.Service
 	AuthenticationService      authn.Service
+	federationService          federation.Service
 	backupKeyShareService      crypto.BackupKeyShareService
 	rootKeyShareExpirationTime time.Duration
 	selfOrgID                  string
+	uploadLimits               config.UploadLimits
 
 	// storers
 	ssoDB   *sql.DB
@@ -32,9 +37,11 @@ func NewSSOService(
 	ids identity.Service,
 	afs authflow.Service,
 	authns authn.Service,
+	fs federation.Service,
 	bks crypto.BackupKeyShareService,
 	rootKeyShareExpirationTime time.Duration,
 	selfOrgID string,
+	uploadLimits config.UploadLimits,
 
 	ssoDB, boxDB *sql.DB,
 	redConn *redis.Client,
@@ -43,9 +50,11 @@ func NewSSOService(
 		identityService:            ids,
 		authFlowService:            afs,
 		AuthenticationService:      authns,
+		federationService:          fs,
 		backupKeyShareService:      bks,
 		rootKeyShareExpirationTime: rootKeyShareExpirationTime,
 		selfOrgID:                  selfOrgID,
+		uploadLimits:               uploadLimits,
 
 		ssoDB:   ssoDB,
 		boxDB:   boxDB,
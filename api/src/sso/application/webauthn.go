@@ -24,8 +24,11 @@ import (
 )
 
 // BeginWebAuthnRegistrationQuery ...
+// Passwordless credentials are discoverable and verify the user
+// so they can be used alone to login.
 type BeginWebAuthnRegistrationQuery struct {
-	identityID string
+	identityID   string
+	Passwordless bool `query:"passwordless"`
 }
 
 // BindAndValidate ...
@@ -72,7 +75,16 @@ func (sso *SSOService) BeginWebAuthnRegistration(ctx context.Context, gen reques
 		excludeCredentials[i] = credentialDescriptor
 	}
 
-	options, sessionData, err := sso.AuthenticationService.WebauthnHandler.BeginRegistration(&wid, webauthn.WithExclusions(excludeCredentials))
+	registrationOptions := []webauthn.RegistrationOption{webauthn.WithExclusions(excludeCredentials)}
+	if query.Passwordless {
+		requireResidentKey := true
+		registrationOptions = append(registrationOptions, webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			RequireResidentKey: &requireResidentKey,
+			UserVerification:   protocol.VerificationRequired,
+		}))
+	}
+
+	options, sessionData, err := sso.AuthenticationService.WebauthnHandler.BeginRegistration(&wid, registrationOptions...)
 	if err != nil {
 		return nil, merr.From(err).Desc("beginning webauthn registration")
 	}
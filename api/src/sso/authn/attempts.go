@@ -0,0 +1,194 @@
+package authn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+)
+
+// AttemptLimits protects the authn steps asserting a secret (emailed code, password, totp)
+// against brute-force attacks.
+// Failed attempts are counted per identity and per IP address: once a counter reaches its maximum,
+// new attempts are locked for a duration doubling on each further failure.
+type AttemptLimits struct {
+	// failed attempts allowed per identity and per IP before a lockout
+	MaxIdentityFailures int
+	MaxIPFailures       int
+	// failed attempts allowed on an emailed code before it is invalidated
+	MaxCodeFailures int
+	// duration of the first lockout and maximum duration of a lockout
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	// period without failure after which the counters are forgotten
+	Window time.Duration
+}
+
+// DefaultAttemptLimits are used if the limits are not configured
+var DefaultAttemptLimits = AttemptLimits{
+	MaxIdentityFailures: 5,
+	MaxIPFailures:       20,
+	MaxCodeFailures:     3,
+	BaseLockout:         30 * time.Second,
+	MaxLockout:          time.Hour,
+	Window:              time.Hour,
+}
+
+// lockoutDuration returns how long attempts are locked after the given number of failures
+// considering the maximum number of failures allowed - 0 means no lockout
+func (limits AttemptLimits) lockoutDuration(failures, maxFailures int) time.Duration {
+	if maxFailures <= 0 || failures < maxFailures {
+		return 0
+	}
+	lockout := limits.BaseLockout
+	for i := maxFailures; i < failures; i++ {
+		lockout *= 2
+		if lockout >= limits.MaxLockout {
+			return limits.MaxLockout
+		}
+	}
+	if lockout > limits.MaxLockout {
+		return limits.MaxLockout
+	}
+	return lockout
+}
+
+func identityAttemptsKey(identityID string) string {
+	return fmt.Sprintf("authn_attempts:identity:%s", identityID)
+}
+
+func ipAttemptsKey(ip string) string {
+	return fmt.Sprintf("authn_attempts:ip:%s", ip)
+}
+
+func codeAttemptsKey(stepID int) string {
+	return fmt.Sprintf("authn_attempts:step:%d", stepID)
+}
+
+func lockoutKey(attemptsKey string) string {
+	return "lockout:" + attemptsKey
+}
+
+// attemptKeys returns the counters to consider for the identity and the ip
+// the ip is ignored when unknown
+func attemptKeys(identityID, ip string) []string {
+	keys := []string{identityAttemptsKey(identityID)}
+	if ip != "" {
+		keys = append(keys, ipAttemptsKey(ip))
+	}
+	return keys
+}
+
+// mustNotBeLocked returns a too many requests error if the identity or the ip are locked,
+// the error tells when a new attempt can be performed
+func (as *Service) mustNotBeLocked(ctx context.Context, redConn *redis.Client, identityID, ip string) error {
+	var retryAfter time.Duration
+	for _, key := range attemptKeys(identityID, ip) {
+		ttl, err := redConn.PTTL(lockoutKey(key)).Result()
+		if err != nil {
+			return merr.From(err).Desc("getting lockout")
+		}
+		// negative values are returned if the key does not exist or has no expiration
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+	if retryAfter > 0 {
+		return tooManyAttempts(retryAfter)
+	}
+	return nil
+}
+
+// recordFailure of an attempt for the identity and the ip.
+// It returns a too many requests error if the failure has locked further attempts.
+func (as *Service) recordFailure(ctx context.Context, redConn *redis.Client, identityID, ip string) error {
+	var retryAfter time.Duration
+	for _, key := range attemptKeys(identityID, ip) {
+		maxFailures := as.attempts.MaxIdentityFailures
+		if key != identityAttemptsKey(identityID) {
+			maxFailures = as.attempts.MaxIPFailures
+		}
+		failures, err := redConn.Incr(key).Result()
+		if err != nil {
+			return merr.From(err).Desc("incrementing failures")
+		}
+		lockout := as.attempts.lockoutDuration(int(failures), maxFailures)
+		// keep the counter during the lockout so the next one is longer
+		if _, err := redConn.Expire(key, as.attempts.Window+lockout).Result(); err != nil {
+			return merr.From(err).Desc("expiring failures")
+		}
+		if lockout == 0 {
+			continue
+		}
+		if _, err := redConn.Set(lockoutKey(key), strconv.FormatInt(failures, 10), lockout).Result(); err != nil {
+			return merr.From(err).Desc("setting lockout")
+		}
+		if lockout > retryAfter {
+			retryAfter = lockout
+		}
+	}
+	if retryAfter > 0 {
+		return tooManyAttempts(retryAfter)
+	}
+	return nil
+}
+
+// failedAttempt records the failure and returns the error to send back:
+// the lockout error if the failure has locked further attempts, the assertion error otherwise
+func (as *Service) failedAttempt(ctx context.Context, redConn *redis.Client, identityID, ip string, assertionErr error) error {
+	if err := as.recordFailure(ctx, redConn, identityID, ip); err != nil {
+		return err
+	}
+	return assertionErr
+}
+
+// resetFailures of the identity after a successful attempt.
+// The ip counter is kept: an attacker must not be able to reset it using its own identity.
+func (as *Service) resetFailures(ctx context.Context, redConn *redis.Client, identityID string) error {
+	key := identityAttemptsKey(identityID)
+	if _, err := redConn.Del(key, lockoutKey(key)).Result(); err != nil {
+		return merr.From(err).Desc("resetting failures")
+	}
+	return nil
+}
+
+// recordCodeFailure of an attempt on an emailed code step.
+// It returns true if the code must be considered as invalidated.
+func (as *Service) recordCodeFailure(ctx context.Context, redConn *redis.Client, stepID int) (bool, error) {
+	key := codeAttemptsKey(stepID)
+	failures, err := redConn.Incr(key).Result()
+	if err != nil {
+		return false, merr.From(err).Desc("incrementing code failures")
+	}
+	// the code cannot be used after its validity anyway
+	if _, err := redConn.Expire(key, as.codeValidity).Result(); err != nil {
+		return false, merr.From(err).Desc("expiring code failures")
+	}
+	return int(failures) >= as.attempts.MaxCodeFailures, nil
+}
+
+// isCodeInvalidated by too many failed attempts
+func (as *Service) isCodeInvalidated(ctx context.Context, redConn *redis.Client, stepID int) (bool, error) {
+	failures, err := redConn.Get(codeAttemptsKey(stepID)).Int()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, merr.From(err).Desc("getting code failures")
+	}
+	return failures >= as.attempts.MaxCodeFailures, nil
+}
+
+func tooManyAttempts(retryAfter time.Duration) error {
+	// round up to the next second so the client does not retry too early
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	return merr.TooManyRequests().
+		Desc("too many failed attempts").
+		Add("metadata", merr.DVLocked).
+		Add("retry_after", strconv.FormatInt(seconds, 10)).
+		Add("retry_at", time.Now().Add(time.Duration(seconds)*time.Second).UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,51 @@
+package authn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	limits := AttemptLimits{
+		BaseLockout: 30 * time.Second,
+		MaxLockout:  5 * time.Minute,
+	}
+	tests := map[string]struct {
+		failures    int
+		maxFailures int
+		expected    time.Duration
+	}{
+		"no lockout under the maximum": {
+			failures:    4,
+			maxFailures: 5,
+			expected:    0,
+		},
+		"first lockout at the maximum": {
+			failures:    5,
+			maxFailures: 5,
+			expected:    30 * time.Second,
+		},
+		"lockout doubles on each further failure": {
+			failures:    7,
+			maxFailures: 5,
+			expected:    2 * time.Minute,
+		},
+		"lockout is capped": {
+			failures:    50,
+			maxFailures: 5,
+			expected:    5 * time.Minute,
+		},
+		"no lockout without maximum": {
+			failures:    50,
+			maxFailures: 0,
+			expected:    0,
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			assert.Equal(t, test.expected, limits.lockoutDuration(test.failures, test.maxFailures))
+		})
+	}
+}
@@ -23,6 +23,7 @@ type Service struct {
 	emails    email.Sender
 
 	codeValidity time.Duration
+	attempts     AttemptLimits
 
 	WebauthnHandler *webauthn.WebAuthn
 	AppName         string
@@ -31,6 +32,8 @@ type Service struct {
 type sessionRepo interface {
 	Upsert(context.Context, Session, time.Duration) error
 	Get(context.Context, string) (Session, error)
+	List(ctx context.Context, identityID string) ([]Session, error)
+	Delete(ctx context.Context, sessionID string, identityID string) error
 }
 
 // NewService ...
@@ -38,6 +41,7 @@ func NewService(
 	sessions sessionRepo, processes processRepo,
 	templates email.Renderer, emails email.Sender,
 	webauthnHandler *webauthn.WebAuthn, appName string,
+	attempts AttemptLimits,
 ) Service {
 	return Service{
 		sessions:        sessions,
@@ -45,6 +49,7 @@ func NewService(
 		templates:       templates,
 		emails:          emails,
 		codeValidity:    5 * time.Minute,
+		attempts:        attempts,
 		WebauthnHandler: webauthnHandler,
 		AppName:         appName,
 	}
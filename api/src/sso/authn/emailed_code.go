@@ -17,20 +17,27 @@ import (
 )
 
 // createEmailedCode authentication step
-func (as *Service) createEmailedCode(ctx context.Context, exec boil.ContextExecutor, identity identity.Identity) error {
+func (as *Service) createEmailedCode(ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client, identity identity.Identity) error {
 	// try to retrieve an existing code for this identity
 	existing, err := getLastStep(ctx, exec, identity.ID, oidc.AMREmailedCode)
 	if err != nil && !merr.IsANotFound(err) {
 		return err
 	}
 	// if the last authn step is not complete and not expired, we can't create a new one
+	// except if it has been invalidated by too many failed attempts
 	if err == nil &&
 		!existing.Complete &&
 		time.Since(existing.CreatedAt) < as.codeValidity {
-		return merr.Conflict().
-			Desc("a code has already been generated and not used").
-			Add("identity_id", merr.DVConflict).
-			Add("method_name", merr.DVConflict)
+		invalidated, err := as.isCodeInvalidated(ctx, redConn, existing.ID)
+		if err != nil {
+			return err
+		}
+		if !invalidated {
+			return merr.Conflict().
+				Desc("a code has already been generated and not used").
+				Add("identity_id", merr.DVConflict).
+				Add("method_name", merr.DVConflict)
+		}
 	}
 
 	codeRawJSON, err := code.GenerateAsRawJSON()
@@ -74,13 +81,13 @@ func (as *Service) createEmailedCode(ctx context.Context, exec boil.ContextExecu
 }
 
 func prepareEmailedCode(
-	ctx context.Context, as *Service, exec boil.ContextExecutor, _ *redis.Client,
+	ctx context.Context, as *Service, exec boil.ContextExecutor, redConn *redis.Client,
 	identity identity.Identity, currentACR oidc.ClassRef, step *Step,
 	_ bool,
 ) (*Step, error) {
 	step.MethodName = oidc.AMREmailedCode
 	// we ignore the conflict error code - if a code already exist, we still want to return identity information
-	err := as.createEmailedCode(ctx, exec, identity)
+	err := as.createEmailedCode(ctx, exec, redConn, identity)
 	// set the error to nil on conflict because we want to fail silently
 	// if an emailed code was already generated
 	if merr.IsAConflict(err) {
@@ -90,8 +97,8 @@ func prepareEmailedCode(
 }
 
 func (as *Service) assertEmailedCode(
-	ctx context.Context, exec boil.ContextExecutor,
-	assertion Step,
+	ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client,
+	ip string, assertion Step,
 ) error {
 	// always take the most recent step as the current one - ignore others
 	currentStep, err := getLastStep(ctx, exec, assertion.IdentityID, assertion.MethodName)
@@ -102,6 +109,14 @@ func (as *Service) assertEmailedCode(
 	if currentStep.Complete {
 		return merr.Conflict().Desc("emailed code already complete")
 	}
+	// check the most recent step has not been invalidated by too many failed attempts
+	invalidated, err := as.isCodeInvalidated(ctx, redConn, currentStep.ID)
+	if err != nil {
+		return err
+	}
+	if invalidated {
+		return codeInvalidated()
+	}
 
 	// transform metadata into code metadata structure
 	input, err := code.ToMetadata(assertion.RawJSONMetadata)
@@ -119,7 +134,15 @@ func (as *Service) assertEmailedCode(
 	// try to match codes
 	match := stored.Matches(input)
 	if !match {
-		return merr.Forbidden().Ori(merr.OriBody).Add("metadata", merr.DVInvalid)
+		invalidated, err := as.recordCodeFailure(ctx, redConn, currentStep.ID)
+		if err != nil {
+			return err
+		}
+		assertionErr := merr.Forbidden().Ori(merr.OriBody).Add("metadata", merr.DVInvalid)
+		if invalidated {
+			assertionErr = codeInvalidated()
+		}
+		return as.failedAttempt(ctx, redConn, assertion.IdentityID, ip, assertionErr)
 	}
 
 	// check stored code is not expired
@@ -130,3 +153,11 @@ func (as *Service) assertEmailedCode(
 	// complete the authentication step
 	return completeAtStep(ctx, exec, currentStep.ID, time.Now())
 }
+
+// codeInvalidated is returned when the emailed code has received too many wrong attempts,
+// a new code must be generated by the end-user
+func codeInvalidated() merr.Error {
+	return merr.Forbidden().Ori(merr.OriBody).
+		Desc("too many failed attempts on the code, a new one must be requested").
+		Add("metadata", merr.DVExpired)
+}
@@ -83,8 +83,8 @@ func requireAccount(
 }
 
 func (as *Service) assertPassword(
-	ctx context.Context, exec boil.ContextExecutor,
-	curIdentity identity.Identity, assertion Step,
+	ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client,
+	ip string, curIdentity identity.Identity, assertion Step,
 ) error {
 	// transform metadata into argon2 password metadata structure
 	pwdMetadata, err := argon2.ToMetadata(assertion.RawJSONMetadata)
@@ -110,8 +110,8 @@ func (as *Service) assertPassword(
 		return err
 	}
 	if !pwdIsValid {
-		return merr.Forbidden().Desc("invalid password").
-			Ori(merr.OriBody).Add("metadata", merr.DVInvalid)
+		return as.failedAttempt(ctx, redConn, curIdentity.ID, ip, merr.Forbidden().Desc("invalid password").
+			Ori(merr.OriBody).Add("metadata", merr.DVInvalid))
 	}
 	return nil
 }
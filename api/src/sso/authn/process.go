@@ -110,6 +110,19 @@ func (as *Service) UpdateProcess(
 	return nil
 }
 
+// addProcessAMR performed along an authn step
+func (as *Service) addProcessAMR(ctx context.Context, challenge string, amr oidc.MethodRef) error {
+	process, err := as.processes.Get(ctx, challenge)
+	if err != nil {
+		return merr.From(err).Desc("getting process")
+	}
+	process.CompleteAMRs.Add(amr)
+	if err := as.processes.Update(ctx, process); err != nil {
+		return merr.From(err).Desc("updating process")
+	}
+	return nil
+}
+
 // UpgradeProcess by adding an amr on it
 // it inits the process if required,
 // it returns the upgraded Process, telling the login flow require more authn-step to be performed if a NextStep has been set.
@@ -20,6 +20,13 @@ type Session struct {
 	IdentityID  string        `json:"mid"`
 	AccountID   null.String   `json:"aid"`
 	RememberFor int
+
+	// information about the device using the session, updated on each use
+	UserAgent  string    `json:"ua"`
+	IPAddress  string    `json:"ip"`
+	CreatedAt  time.Time `json:"cat"`
+	LastUsedAt time.Time `json:"lat"`
+	ExpiresAt  time.Time `json:"exp"`
 }
 
 // UpsertSession ...
@@ -29,22 +36,61 @@ func (as *Service) UpsertSession(ctx context.Context, new Session) error {
 		return nil
 	}
 
+	lifetime := time.Duration(new.RememberFor) * time.Second
+	now := time.Now()
+	new.CreatedAt = now
+	new.LastUsedAt = now
+	new.ExpiresAt = now.Add(lifetime)
+
 	existing, err := as.sessions.Get(ctx, new.ID)
 	if err != nil {
 		// if not found, we ignore the error to create it
 		if !merr.IsANotFound(err) {
 			return err
 		}
-		// if found but the new sec level is inferior to the new session, we skip it
-	} else if existing.ACR > new.ACR {
-		return nil
+	} else if existing.IdentityID != new.IdentityID {
+		// the login session is now used by another identity: the previous session is replaced
+		if err := as.sessions.Delete(ctx, existing.ID, existing.IdentityID); err != nil {
+			return err
+		}
+	} else {
+		if !existing.CreatedAt.IsZero() {
+			new.CreatedAt = existing.CreatedAt
+		}
+		// if found but the new sec level is inferior to the existing session,
+		// the existing session is kept and only its use is recorded
+		if existing.ACR > new.ACR {
+			return as.touchSession(ctx, existing, new)
+		}
 	}
 
-	lifetime := time.Duration(new.RememberFor) * time.Second
 	return as.sessions.Upsert(ctx, new, lifetime)
 }
 
+// touchSession records the use of an existing session without changing its lifetime
+func (as *Service) touchSession(ctx context.Context, existing Session, use Session) error {
+	lifetime := time.Until(existing.ExpiresAt)
+	// sessions created before the expiration was stored are left untouched
+	if existing.ExpiresAt.IsZero() || lifetime <= 0 {
+		return nil
+	}
+	existing.UserAgent = use.UserAgent
+	existing.IPAddress = use.IPAddress
+	existing.LastUsedAt = use.LastUsedAt
+	return as.sessions.Upsert(ctx, existing, lifetime)
+}
+
 // GetSession ...
 func (as *Service) GetSession(ctx context.Context, sessionID string) (Session, error) {
 	return as.sessions.Get(ctx, sessionID)
 }
+
+// ListSessions of the identity
+func (as *Service) ListSessions(ctx context.Context, identityID string) ([]Session, error) {
+	return as.sessions.List(ctx, identityID)
+}
+
+// DeleteSession so it cannot be used anymore to skip a login flow
+func (as *Service) DeleteSession(ctx context.Context, session Session) error {
+	return as.sessions.Delete(ctx, session.ID, session.IdentityID)
+}
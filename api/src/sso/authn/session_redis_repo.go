@@ -3,8 +3,11 @@ package authn
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
+	"github.com/go-redis/redis/v7"
+
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/mredis"
 )
@@ -12,15 +15,41 @@ import (
 // SessionRedisRepo ...
 type SessionRedisRepo struct {
 	mredis.SimpleKeyRedis
+	redConn *redis.Client
 }
 
 // NewAuthnSessionRedis ...
-func NewAuthnSessionRedis(skr mredis.SimpleKeyRedis) SessionRedisRepo {
-	return SessionRedisRepo{skr}
+func NewAuthnSessionRedis(skr mredis.SimpleKeyRedis, redConn *redis.Client) SessionRedisRepo {
+	return SessionRedisRepo{skr, redConn}
 }
 
+const sessionKeyPrefix = "authn_session:"
+
 func (srr SessionRedisRepo) key(sessionID string) string {
-	return "authn_session:" + sessionID
+	return sessionKeyPrefix + sessionID
+}
+
+// indexKey of the set of session ids of an identity, used to list its sessions
+func (srr SessionRedisRepo) indexKey(identityID string) string {
+	return "authn_sessions:" + identityID
+}
+
+// index the session for its identity, the index lives as long as its longest session
+func (srr SessionRedisRepo) index(sessionID string, identityID string, lifetime time.Duration) error {
+	indexKey := srr.indexKey(identityID)
+	if _, err := srr.redConn.SAdd(indexKey, sessionID).Result(); err != nil {
+		return merr.From(err).Desc("indexing session")
+	}
+	ttl, err := srr.redConn.TTL(indexKey).Result()
+	if err != nil {
+		return merr.From(err).Desc("getting session index ttl")
+	}
+	if ttl < lifetime {
+		if _, err := srr.redConn.Expire(indexKey, lifetime).Result(); err != nil {
+			return merr.From(err).Desc("expiring session index")
+		}
+	}
+	return nil
 }
 
 // Upsert ...
@@ -29,13 +58,15 @@ func (srr SessionRedisRepo) Upsert(ctx context.Context, session Session, lifetim
 	if err != nil {
 		return merr.From(err).Desc("marshaling sesion")
 	}
-	return srr.SimpleKeyRedis.Set(ctx, srr.key(session.ID), value, lifetime)
+	if err := srr.SimpleKeyRedis.Set(ctx, srr.key(session.ID), value, lifetime); err != nil {
+		return err
+	}
+	return srr.index(session.ID, session.IdentityID, lifetime)
 }
 
 // Get ...
 func (srr SessionRedisRepo) Get(ctx context.Context, sessionID string) (Session, error) {
 	session := Session{}
-
 	value, err := srr.SimpleKeyRedis.Get(ctx, srr.key(sessionID))
 	if err != nil {
 		return session, err
@@ -46,3 +77,104 @@ func (srr SessionRedisRepo) Get(ctx context.Context, sessionID string) (Session,
 	session.ID = sessionID
 	return session, nil
 }
+
+// List ...
+func (srr SessionRedisRepo) List(ctx context.Context, identityID string) ([]Session, error) {
+	sessions := []Session{}
+
+	sessionIDs, err := srr.redConn.SMembers(srr.indexKey(identityID)).Result()
+	if err != nil {
+		return nil, merr.From(err).Desc("listing indexed sessions")
+	}
+	if len(sessionIDs) == 0 {
+		return sessions, nil
+	}
+	keys := make([]string, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		keys[i] = srr.key(sessionID)
+	}
+	values, err := srr.redConn.MGet(keys...).Result()
+	if err != nil {
+		return nil, merr.From(err).Desc("getting sessions")
+	}
+
+	var outdated []interface{}
+	for i, elem := range values {
+		session := Session{}
+		// sessions might have expired since they have been indexed
+		value, ok := elem.(string)
+		if ok {
+			if err := json.Unmarshal([]byte(value), &session); err != nil {
+				return nil, merr.From(err).Desc("unmarshaling session")
+			}
+		}
+		// the login session might also be used by another identity now
+		if !ok || session.IdentityID != identityID {
+			outdated = append(outdated, sessionIDs[i])
+			continue
+		}
+		session.ID = sessionIDs[i]
+		sessions = append(sessions, session)
+	}
+	if len(outdated) > 0 {
+		if _, err := srr.redConn.SRem(srr.indexKey(identityID), outdated...).Result(); err != nil {
+			return nil, merr.From(err).Desc("removing outdated sessions from index")
+		}
+	}
+	return sessions, nil
+}
+
+// Delete ...
+func (srr SessionRedisRepo) Delete(ctx context.Context, sessionID string, identityID string) error {
+	if err := srr.SimpleKeyRedis.Flush(ctx, srr.key(sessionID)); err != nil {
+		return merr.From(err).Desc("deleting session")
+	}
+	if _, err := srr.redConn.SRem(srr.indexKey(identityID), sessionID).Result(); err != nil {
+		return merr.From(err).Desc("removing session from index")
+	}
+	return nil
+}
+
+// IndexLegacySessions stored before sessions were indexed per identity so they can be listed.
+// It can be run several times.
+func (srr SessionRedisRepo) IndexLegacySessions(ctx context.Context) error {
+	var cursor uint64
+	for {
+		keys, next, err := srr.redConn.Scan(cursor, sessionKeyPrefix+"*", 100).Result()
+		if err != nil {
+			return merr.From(err).Desc("scanning sessions")
+		}
+		for _, key := range keys {
+			if err := srr.indexLegacySession(ctx, key); err != nil {
+				return merr.From(err).Descf("indexing %s", key)
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (srr SessionRedisRepo) indexLegacySession(ctx context.Context, key string) error {
+	value, err := srr.SimpleKeyRedis.Get(ctx, key)
+	if err != nil {
+		// the key might have expired since it has been scanned
+		if merr.IsANotFound(err) {
+			return nil
+		}
+		return err
+	}
+	lifetime, err := srr.redConn.TTL(key).Result()
+	if err != nil {
+		return merr.From(err).Desc("getting session ttl")
+	}
+	if lifetime <= 0 {
+		return nil
+	}
+	session := Session{}
+	if err := json.Unmarshal(value, &session); err != nil {
+		return merr.From(err).Desc("unmarshaling session")
+	}
+	return srr.index(strings.TrimPrefix(key, sessionKeyPrefix), session.IdentityID, lifetime)
+}
@@ -27,54 +27,83 @@ type Step struct {
 	CompleteAt      null.Time
 }
 
-// InitStep ...
+// InitStep for the identity.
+// A nil identity inits a usernameless step, only possible with webauthn:
+// the prepared step is then returned so its metadata can be sent to the end-user.
 func (as *Service) InitStep(
 	ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client,
-	identity identity.Identity, methodName oidc.MethodRef,
-) error {
+	identity *identity.Identity, methodName oidc.MethodRef,
+) (*Step, error) {
+	if identity == nil {
+		if methodName != oidc.AMRWebauthn {
+			return nil, merr.BadRequest().Desc("only webauthn can be init without identity").Add("method_name", merr.DVInvalid)
+		}
+		return prepareUsernamelessWebauthn(as, redConn, &Step{})
+	}
+
 	switch methodName {
 	case oidc.AMREmailedCode:
-		_, err := prepareEmailedCode(ctx, as, exec, redConn, identity, oidc.ACR0, &Step{}, false)
-		return err
+		_, err := prepareEmailedCode(ctx, as, exec, redConn, *identity, oidc.ACR0, &Step{}, false)
+		return nil, err
 	case oidc.AMRPrehashedPassword:
-		return assertPasswordExistence(ctx, identity)
+		return nil, assertPasswordExistence(ctx, *identity)
 	case oidc.AMRWebauthn:
-		return assertWebauthnCredentials(ctx, exec, identity)
+		return nil, assertWebauthnCredentials(ctx, exec, *identity)
 	case oidc.AMRTOTP:
-		return assertTOTPSecret(ctx, exec, identity)
+		return nil, assertTOTPSecret(ctx, exec, *identity)
 	default:
-		return merr.BadRequest().Desc("cannot init method").Add("method_name", merr.DVInvalid)
+		return nil, merr.BadRequest().Desc("cannot init method").Add("method_name", merr.DVInvalid)
 	}
 }
 
 // AssertStep considering the method name and the received metadata
 // It takes a pointer on the identity since the identity might be atlered by the authn step
+// The ip of the end-user is used to limit failed attempts, it can be empty if unknown
 // Return a nil error in case of success
 func (as *Service) AssertStep(
 	ctx context.Context, tr *sql.Tx, redConn *redis.Client,
-	challenge string, identity *identity.Identity, assertion Step,
+	challenge string, ip string, identity *identity.Identity, assertion Step,
 ) error {
+	// methods asserting a secret are protected against brute-force attacks
+	guarded := isGuardedMethod(assertion.MethodName)
+	if guarded {
+		if err := as.mustNotBeLocked(ctx, redConn, identity.ID, ip); err != nil {
+			return err
+		}
+	}
+
 	// check the metadata
 	var metadataErr error
 	switch assertion.MethodName {
 	case oidc.AMREmailedCode:
-		metadataErr = as.assertEmailedCode(ctx, tr, assertion)
+		metadataErr = as.assertEmailedCode(ctx, tr, redConn, ip, assertion)
 	case oidc.AMRPrehashedPassword:
-		metadataErr = as.assertPassword(ctx, tr, *identity, assertion)
+		metadataErr = as.assertPassword(ctx, tr, redConn, ip, *identity, assertion)
 	case oidc.AMRAccountCreation:
 		metadataErr = as.assertAccountCreation(ctx, tr, redConn, challenge, identity, assertion)
 	case oidc.AMRWebauthn:
-		metadataErr = as.assertWebauthn(ctx, tr, redConn, *identity, assertion)
+		metadataErr = as.assertWebauthn(ctx, tr, redConn, challenge, *identity, assertion)
 	case oidc.AMRTOTP:
-		metadataErr = as.assertTOTP(ctx, tr, redConn, *identity, assertion)
+		metadataErr = as.assertTOTP(ctx, tr, redConn, ip, *identity, assertion)
 	case oidc.AMRResetPassword:
 		metadataErr = as.resetPassword(ctx, tr, redConn, challenge, *identity, assertion)
 	default:
 		metadataErr = merr.BadRequest().Add("method_name", merr.DVMalformed)
 	}
+	if guarded && metadataErr == nil {
+		return as.resetFailures(ctx, redConn, identity.ID)
+	}
 	return metadataErr
 }
 
+func isGuardedMethod(methodName oidc.MethodRef) bool {
+	switch methodName {
+	case oidc.AMREmailedCode, oidc.AMRPrehashedPassword, oidc.AMRTOTP:
+		return true
+	}
+	return false
+}
+
 type authnMethodHandler func(
 	context.Context, *Service, boil.ContextExecutor, *redis.Client,
 	identity.Identity, oidc.ClassRef, *Step,
@@ -55,8 +55,8 @@ type totpAssertion struct {
 }
 
 func (as *Service) assertTOTP(
-	ctx context.Context, exec boil.ContextExecutor, _ *redis.Client,
-	curIdentity identity.Identity, assertion Step) error {
+	ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client,
+	ip string, curIdentity identity.Identity, assertion Step) error {
 
 	mods := []qm.QueryMod{
 		sqlboiler.TotpSecretWhere.IdentityID.EQ(curIdentity.ID),
@@ -76,10 +76,13 @@ func (as *Service) assertTOTP(
 	// and if there is not, there may be a recovery code
 	if content.Code != "" {
 		if !totp.Validate(content.Code, secret.Secret) {
-			return merr.Forbidden().Ori(merr.OriBody).Add("metadata", merr.DVInvalid)
+			return as.failedAttempt(ctx, redConn, curIdentity.ID, ip, merr.Forbidden().Ori(merr.OriBody).Add("metadata", merr.DVInvalid))
 		}
 	} else if content.RecoveryCode != "" {
 		if err := mtotp.CheckAndDeleteRecoveryCode(ctx, exec, secret.ID, content.RecoveryCode); err != nil {
+			if merr.IsAForbidden(err) {
+				return as.failedAttempt(ctx, redConn, curIdentity.ID, ip, err)
+			}
 			return err
 		}
 	} else {
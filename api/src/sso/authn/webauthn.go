@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
 	"github.com/go-redis/redis/v7"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 
@@ -65,23 +66,84 @@ func prepareWebauthn(
 	return step, nil
 }
 
+// usernamelessSessionID replaces the identity id to store the webauthn sessions of usernameless steps
+// since the identity is only known once the assertion is received
+const usernamelessSessionID = "usernameless"
+
+// prepareUsernamelessWebauthn step asking for any discoverable credential (passkey) of the relying party.
+// The authenticator must verify the user so the step is enough to authenticate.
+func prepareUsernamelessWebauthn(as *Service, redConn *redis.Client, step *Step) (*Step, error) {
+	challenge, err := protocol.CreateChallenge()
+	if err != nil {
+		return step, merr.From(err).Desc("creating challenge")
+	}
+
+	// no allowed credentials are set so the authenticator proposes its discoverable ones
+	options := protocol.CredentialAssertion{Response: protocol.PublicKeyCredentialRequestOptions{
+		Challenge:        challenge,
+		Timeout:          as.WebauthnHandler.Config.Timeout,
+		RelyingPartyID:   as.WebauthnHandler.Config.RPID,
+		UserVerification: protocol.VerificationRequired,
+	}}
+	sessionData := webauthn.SessionData{
+		Challenge:        challenge.String(),
+		UserVerification: protocol.VerificationRequired,
+	}
+
+	step.MethodName = oidc.AMRWebauthn
+	if err := step.RawJSONMetadata.Marshal(options); err != nil {
+		return step, merr.From(err).Desc("marshalling webauthn options")
+	}
+	if err := mwebauthn.StoreSession(redConn, &sessionData, usernamelessSessionID, challenge.String()); err != nil {
+		return step, merr.From(err).Desc("storing session")
+	}
+	return step, nil
+}
+
+// UsernamelessIdentityID returns the id of the identity owning the discoverable credential
+// used in the webauthn assertion - it is read from the user handle set by the authenticator
+func UsernamelessIdentityID(assertion Step) (string, error) {
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBody(strings.NewReader(assertion.RawJSONMetadata.String()))
+	if err != nil {
+		return "", merr.Forbidden().Ori(merr.OriBody).Desc(err.Error()).Add("metadata", merr.DVMalformed)
+	}
+	if len(parsedResponse.Response.UserHandle) == 0 {
+		return "", merr.Forbidden().Ori(merr.OriBody).
+			Desc("the credential is not discoverable").Add("metadata", merr.DVInvalid)
+	}
+	return string(parsedResponse.Response.UserHandle), nil
+}
+
 func (as *Service) assertWebauthn(
 	ctx context.Context, exec boil.ContextExecutor, redConn *redis.Client,
-	curIdentity identity.Identity, assertion Step) error {
+	challenge string, curIdentity identity.Identity, assertion Step) error {
 
 	parsedResponse, err := protocol.ParseCredentialRequestResponseBody(strings.NewReader(assertion.RawJSONMetadata.String()))
 	if err != nil {
 		return merr.From(err).Desc("parsing credentials")
 	}
 
-	sessionData, err := mwebauthn.GetSession(redConn, curIdentity.ID, parsedResponse.Response.CollectedClientData.Challenge)
+	wid, err := identity.GetWebauthnIdentity(ctx, exec, curIdentity)
 	if err != nil {
-		return merr.From(err).Desc("getting session")
+		return merr.From(err).Desc("getting webauthn identity")
 	}
 
-	wid, err := identity.GetWebauthnIdentity(ctx, exec, curIdentity)
+	// the session has been stored for the identity or, for usernameless steps, before knowing it
+	webauthnChallenge := parsedResponse.Response.CollectedClientData.Challenge
+	usernameless := false
+	sessionData, err := mwebauthn.GetSession(redConn, curIdentity.ID, webauthnChallenge)
 	if err != nil {
-		return merr.From(err).Desc("getting webauthn identity")
+		sessionData, err = mwebauthn.GetSession(redConn, usernamelessSessionID, webauthnChallenge)
+		if err != nil {
+			return merr.From(err).Desc("getting session")
+		}
+		usernameless = true
+		// the user handle is mandatory to bind the session to the identity
+		if string(parsedResponse.Response.UserHandle) != curIdentity.ID {
+			return merr.Forbidden().Ori(merr.OriBody).
+				Desc("user handle does not match the identity").Add("metadata", merr.DVInvalid)
+		}
+		sessionData.UserID = wid.WebAuthnID()
 	}
 
 	_, err = as.WebauthnHandler.ValidateLogin(&wid, sessionData, parsedResponse)
@@ -89,5 +151,18 @@ func (as *Service) assertWebauthn(
 		return merr.From(err).Desc("validating login")
 	}
 
+	// usernameless sessions are not bound to an identity so they are consumed on use
+	if usernameless {
+		if err := mwebauthn.DeleteSession(redConn, usernamelessSessionID, webauthnChallenge); err != nil {
+			return merr.From(err).Desc("deleting session")
+		}
+	}
+
+	// a verified user makes the webauthn step enough to authenticate
+	if parsedResponse.Response.AuthenticatorData.Flags.UserVerified() {
+		if err := as.addProcessAMR(ctx, challenge, oidc.AMRUserVerification); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,27 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func initAddOrganizationMaxFileSize() {
+	goose.AddMigration(upAddOrganizationMaxFileSize, downAddOrganizationMaxFileSize)
+}
+
+func upAddOrganizationMaxFileSize(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE organization
+			ADD COLUMN max_file_size BIGINT;
+	`)
+	return err
+}
+
+func downAddOrganizationMaxFileSize(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE organization
+			DROP COLUMN max_file_size;
+	`)
+	return err
+}
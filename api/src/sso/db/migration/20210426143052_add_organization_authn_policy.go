@@ -0,0 +1,27 @@
+package migration
+
+import (
+	"database/sql"
+
+	"github.com/pressly/goose"
+)
+
+func initAddOrganizationAuthnPolicy() {
+	goose.AddMigration(upAddOrganizationAuthnPolicy, downAddOrganizationAuthnPolicy)
+}
+
+func upAddOrganizationAuthnPolicy(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE organization
+			ADD COLUMN authn_policy JSONB;
+	`)
+	return err
+}
+
+func downAddOrganizationAuthnPolicy(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE organization
+			DROP COLUMN authn_policy;
+	`)
+	return err
+}
@@ -37,6 +37,8 @@ func Launch() {
 	initCreateDatatagTable()
 	initAddIdentityRsaPubkeys()
 	initResizeCryptoColumns()
+	initAddOrganizationMaxFileSize()
+	initAddOrganizationAuthnPolicy()
 
 	db.StartMigration(os.Getenv("DSN_SSO"), os.Getenv("MIGRATION_DIR_SSO"))
 }
@@ -0,0 +1,327 @@
+package federation
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/mrand"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/mredis"
+)
+
+// Provider is an upstream OpenID Connect provider the end-users can login with.
+// The email returned by the provider is used to find the identity of the end-user,
+// so the provider is only trusted for the configured email domains.
+// The ID token returned by the provider is verified using its issuer and its published keys.
+type Provider struct {
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	Issuer       string   `mapstructure:"issuer"`
+	AuthURL      string   `mapstructure:"auth_url"`
+	TokenURL     string   `mapstructure:"token_url"`
+	UserInfoURL  string   `mapstructure:"userinfo_url"`
+	JWKSURL      string   `mapstructure:"jwks_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	Domains      []string `mapstructure:"domains"`
+}
+
+// Validate the configuration of the provider
+func (p Provider) Validate() error {
+	if p.ClientID == "" || p.Issuer == "" || p.AuthURL == "" || p.TokenURL == "" || p.UserInfoURL == "" || p.JWKSURL == "" {
+		return fmt.Errorf("client_id, issuer, auth_url, token_url, userinfo_url and jwks_url are required")
+	}
+	if len(p.Domains) == 0 {
+		return fmt.Errorf("at least one email domain is required")
+	}
+	return nil
+}
+
+// trusts returns true if the provider is allowed to authenticate the email
+func (p Provider) trusts(email string) bool {
+	parts := strings.Split(email, "@")
+	domain := parts[len(parts)-1]
+	for _, trusted := range p.Domains {
+		if strings.EqualFold(domain, trusted) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Provider) oauth2Config(redirectURL string) oauth2.Config {
+	scopes := p.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email"}
+	}
+	return oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  redirectURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.AuthURL,
+			TokenURL: p.TokenURL,
+		},
+		Scopes: scopes,
+	}
+}
+
+// State of a federated login, it binds the upstream authorization request to the login flow
+type State struct {
+	LoginChallenge string `json:"lgc"`
+	Provider       string `json:"provider"`
+	// Binding is also set in a cookie of the browser which started the federated login
+	// so the state cannot be used from another browser
+	Binding string `json:"binding"`
+	// CodeVerifier is the PKCE secret proving the code is exchanged by the one who requested it
+	CodeVerifier string `json:"code_verifier"`
+	// Nonce is expected in the ID token to bind it to the authorization request
+	Nonce string `json:"nonce"`
+}
+
+var stateLifetime = 10 * time.Minute
+
+// Service handles login flows delegated to upstream providers
+type Service struct {
+	providers   map[string]Provider
+	redirectURL string
+	states      mredis.SimpleKeyRedis
+}
+
+// NewService ...
+func NewService(providers map[string]Provider, redirectURL string, states mredis.SimpleKeyRedis) Service {
+	return Service{
+		providers:   providers,
+		redirectURL: redirectURL,
+		states:      states,
+	}
+}
+
+func stateKey(state string) string {
+	return "federation_state:" + state
+}
+
+func (s Service) getProvider(name string) (Provider, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return provider, merr.NotFound().Desc("unknown provider").Add("provider", merr.DVNotFound)
+	}
+	return provider, nil
+}
+
+// StateLifetime is how long the end-user has to authenticate on the upstream provider
+func StateLifetime() time.Duration {
+	return stateLifetime
+}
+
+// codeChallenge derived from the PKCE code verifier using the S256 method
+func codeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// BuildAuthURL returns the url of the upstream provider the end-user must be redirected to
+// in order to continue the login flow identified by the challenge,
+// alongside the binding the browser must send back with the state
+func (s Service) BuildAuthURL(ctx context.Context, providerName, loginChallenge string) (string, string, error) {
+	provider, err := s.getProvider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	secrets := make([]string, 4)
+	for i := range secrets {
+		if secrets[i], err = mrand.String(64); err != nil {
+			return "", "", merr.From(err).Desc("generating state secrets")
+		}
+	}
+	state := secrets[0]
+	federationState := State{
+		LoginChallenge: loginChallenge,
+		Provider:       providerName,
+		Binding:        secrets[1],
+		CodeVerifier:   secrets[2],
+		Nonce:          secrets[3],
+	}
+	value, err := json.Marshal(federationState)
+	if err != nil {
+		return "", "", merr.From(err).Desc("marshaling state")
+	}
+	if err := s.states.Set(ctx, stateKey(state), value, stateLifetime); err != nil {
+		return "", "", merr.From(err).Desc("storing state")
+	}
+
+	config := provider.oauth2Config(s.redirectURL)
+	authURL := config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge(federationState.CodeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		oauth2.SetAuthURLParam("nonce", federationState.Nonce),
+	)
+	return authURL, federationState.Binding, nil
+}
+
+// ConsumeState received back from the upstream provider - a state can be used only once
+// and only by the browser which started the federated login, identified by the binding
+func (s Service) ConsumeState(ctx context.Context, state, binding string) (State, error) {
+	var federationState State
+	value, err := s.states.Get(ctx, stateKey(state))
+	if err != nil {
+		if merr.IsANotFound(err) {
+			return federationState, merr.Forbidden().Desc("unknown or expired state").Add("state", merr.DVInvalid)
+		}
+		return federationState, merr.From(err).Desc("getting state")
+	}
+	if err := s.states.Flush(ctx, stateKey(state)); err != nil {
+		return federationState, merr.From(err).Desc("flushing state")
+	}
+	if err := json.Unmarshal(value, &federationState); err != nil {
+		return federationState, merr.From(err).Desc("unmarshaling state")
+	}
+	if binding == "" || subtle.ConstantTimeCompare([]byte(binding), []byte(federationState.Binding)) != 1 {
+		return federationState, merr.Forbidden().Desc("state not bound to the browser").Add("state", merr.DVInvalid)
+	}
+	return federationState, nil
+}
+
+// Email of the end-user authenticated by the provider:
+// the code is exchanged against an ID token, verified against the state,
+// and an access token used to get the user info
+func (s Service) Email(ctx context.Context, state State, code string) (string, error) {
+	provider, err := s.getProvider(state.Provider)
+	if err != nil {
+		return "", err
+	}
+	return provider.email(ctx, s.redirectURL, code, state)
+}
+
+type userInfo struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified *bool  `json:"email_verified"`
+}
+
+func (p Provider) email(ctx context.Context, redirectURL, code string, state State) (string, error) {
+	config := p.oauth2Config(redirectURL)
+	token, err := config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", state.CodeVerifier))
+	if err != nil {
+		return "", merr.Forbidden().Descf("exchanging code: %v", err).Add("code", merr.DVInvalid)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return "", merr.Forbidden().Desc("no id token returned by the provider").Add("id_token", merr.DVRequired)
+	}
+	subject, err := p.verifyIDToken(ctx, rawIDToken, state.Nonce)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := config.Client(ctx, token).Get(p.UserInfoURL)
+	if err != nil {
+		return "", merr.From(err).Desc("getting user info")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", merr.Forbidden().Descf("getting user info: status %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", merr.From(err).Desc("reading user info")
+	}
+	var info userInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", merr.From(err).Desc("unmarshaling user info")
+	}
+	// the user info must describe the end-user authenticated by the ID token
+	if info.Subject != subject {
+		return "", merr.Forbidden().Desc("user info subject does not match the id token").Add("sub", merr.DVInvalid)
+	}
+
+	// the email is the identifier of the end-user, it must be known and verified by the provider
+	if info.Email == "" {
+		return "", merr.Forbidden().Desc("no email returned by the provider").Add("email", merr.DVRequired)
+	}
+	if info.EmailVerified == nil || !*info.EmailVerified {
+		return "", merr.Forbidden().Desc("email not verified by the provider").Add("email", merr.DVInvalid)
+	}
+	if !p.trusts(info.Email) {
+		return "", merr.Forbidden().Desc("email domain not trusted for the provider").Add("email", merr.DVInvalid)
+	}
+	return strings.ToLower(info.Email), nil
+}
+
+type idTokenClaims struct {
+	Nonce string `json:"nonce"`
+}
+
+// verifyIDToken signature using the keys published by the provider, its issuer, audience, expiration and nonce,
+// it returns the subject of the ID token
+func (p Provider) verifyIDToken(ctx context.Context, rawIDToken, nonce string) (string, error) {
+	idToken, err := jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return "", merr.Forbidden().Descf("parsing id token: %v", err).Add("id_token", merr.DVMalformed)
+	}
+	if len(idToken.Headers) == 0 {
+		return "", merr.Forbidden().Desc("id token without header").Add("id_token", merr.DVMalformed)
+	}
+	keys, err := p.keys(ctx)
+	if err != nil {
+		return "", err
+	}
+	matchingKeys := keys.Key(idToken.Headers[0].KeyID)
+	if len(matchingKeys) == 0 {
+		return "", merr.Forbidden().Desc("unknown id token key").Add("id_token", merr.DVInvalid)
+	}
+
+	var claims jwt.Claims
+	var extraClaims idTokenClaims
+	if err := idToken.Claims(matchingKeys[0].Key, &claims, &extraClaims); err != nil {
+		return "", merr.Forbidden().Descf("verifying id token: %v", err).Add("id_token", merr.DVInvalid)
+	}
+	expected := jwt.Expected{
+		Issuer:   p.Issuer,
+		Audience: jwt.Audience{p.ClientID},
+		Time:     time.Now(),
+	}
+	if err := claims.Validate(expected); err != nil {
+		return "", merr.Forbidden().Descf("validating id token: %v", err).Add("id_token", merr.DVInvalid)
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(extraClaims.Nonce), []byte(nonce)) != 1 {
+		return "", merr.Forbidden().Desc("id token nonce does not match").Add("nonce", merr.DVInvalid)
+	}
+	if claims.Subject == "" {
+		return "", merr.Forbidden().Desc("id token without subject").Add("sub", merr.DVRequired)
+	}
+	return claims.Subject, nil
+}
+
+// keys published by the provider to sign its ID tokens
+func (p Provider) keys(ctx context.Context) (jose.JSONWebKeySet, error) {
+	var keySet jose.JSONWebKeySet
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.JWKSURL, nil)
+	if err != nil {
+		return keySet, merr.From(err).Desc("building jwks request")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return keySet, merr.From(err).Desc("getting jwks")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return keySet, merr.Internal().Descf("getting jwks: status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return keySet, merr.From(err).Desc("decoding jwks")
+	}
+	return keySet, nil
+}
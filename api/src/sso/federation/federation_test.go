@@ -0,0 +1,194 @@
+package federation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+)
+
+const (
+	testVerifier = "code-verifier"
+	testNonce    = "nonce"
+)
+
+type testIDToken struct {
+	jwt.Claims
+	Nonce string `json:"nonce"`
+}
+
+// mockProvider is a minimal upstream provider answering the token, user info and jwks requests
+func mockProvider(t *testing.T, userInfo string, idToken func(issuer string) testIDToken) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithHeader("kid", "provider-key"),
+	)
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "valid_code" || r.FormValue("code_verifier") != testVerifier {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		rawIDToken, err := jwt.Signed(signer).Claims(idToken(server.URL)).CompactSerialize()
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"upstream_token","token_type":"bearer","expires_in":3600,"id_token":%q}`, rawIDToken)
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer upstream_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, userInfo)
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		keySet := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: key.Public(), KeyID: "provider-key", Algorithm: string(jose.RS256), Use: "sig"},
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(keySet))
+	})
+	return server
+}
+
+func validIDToken(issuer string) testIDToken {
+	return testIDToken{
+		Claims: jwt.Claims{
+			Issuer:   issuer,
+			Subject:  "42",
+			Audience: jwt.Audience{"misakey"},
+			Expiry:   jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Nonce: testNonce,
+	}
+}
+
+func TestProviderEmail(t *testing.T) {
+	tests := map[string]struct {
+		userInfo string
+		idToken  func(issuer string) testIDToken
+		code     string
+		verifier string
+		expected string
+		errCode  merr.Code
+	}{
+		"verified email": {
+			userInfo: `{"sub":"42","email":"Jean.Dupont@Corp.example","email_verified":true}`,
+			expected: "jean.dupont@corp.example",
+		},
+		"email without verification claim": {
+			userInfo: `{"sub":"42","email":"jean.dupont@corp.example"}`,
+			errCode:  merr.ForbiddenCode,
+		},
+		"invalid code": {
+			userInfo: `{"sub":"42","email":"jean.dupont@corp.example","email_verified":true}`,
+			code:     "invalid_code",
+			errCode:  merr.ForbiddenCode,
+		},
+		"invalid code verifier": {
+			userInfo: `{"sub":"42","email":"jean.dupont@corp.example","email_verified":true}`,
+			verifier: "other-verifier",
+			errCode:  merr.ForbiddenCode,
+		},
+		"unverified email": {
+			userInfo: `{"sub":"42","email":"jean.dupont@corp.example","email_verified":false}`,
+			errCode:  merr.ForbiddenCode,
+		},
+		"missing email": {
+			userInfo: `{"sub":"42"}`,
+			errCode:  merr.ForbiddenCode,
+		},
+		"untrusted domain": {
+			userInfo: `{"sub":"42","email":"jean.dupont@other.example","email_verified":true}`,
+			errCode:  merr.ForbiddenCode,
+		},
+		"user info of another subject": {
+			userInfo: `{"sub":"43","email":"jean.dupont@corp.example","email_verified":true}`,
+			errCode:  merr.ForbiddenCode,
+		},
+		"id token with another nonce": {
+			userInfo: `{"sub":"42","email":"jean.dupont@corp.example","email_verified":true}`,
+			idToken: func(issuer string) testIDToken {
+				idToken := validIDToken(issuer)
+				idToken.Nonce = "replayed"
+				return idToken
+			},
+			errCode: merr.ForbiddenCode,
+		},
+		"id token for another client": {
+			userInfo: `{"sub":"42","email":"jean.dupont@corp.example","email_verified":true}`,
+			idToken: func(issuer string) testIDToken {
+				idToken := validIDToken(issuer)
+				idToken.Audience = jwt.Audience{"other"}
+				return idToken
+			},
+			errCode: merr.ForbiddenCode,
+		},
+		"expired id token": {
+			userInfo: `{"sub":"42","email":"jean.dupont@corp.example","email_verified":true}`,
+			idToken: func(issuer string) testIDToken {
+				idToken := validIDToken(issuer)
+				idToken.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return idToken
+			},
+			errCode: merr.ForbiddenCode,
+		},
+	}
+	for description, test := range tests {
+		t.Run(description, func(t *testing.T) {
+			idToken := test.idToken
+			if idToken == nil {
+				idToken = validIDToken
+			}
+			code := test.code
+			if code == "" {
+				code = "valid_code"
+			}
+			verifier := test.verifier
+			if verifier == "" {
+				verifier = testVerifier
+			}
+
+			server := mockProvider(t, test.userInfo, idToken)
+			defer server.Close()
+			provider := Provider{
+				ClientID:     "misakey",
+				ClientSecret: "secret",
+				Issuer:       server.URL,
+				AuthURL:      server.URL + "/auth",
+				TokenURL:     server.URL + "/token",
+				UserInfoURL:  server.URL + "/userinfo",
+				JWKSURL:      server.URL + "/jwks",
+				Domains:      []string{"corp.example"},
+			}
+			state := State{CodeVerifier: verifier, Nonce: testNonce}
+
+			email, err := provider.email(context.Background(), "https://api.misakey.com/auth/federation/callback", code, state)
+			if test.errCode != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.errCode, merr.From(err).Co)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, email)
+		})
+	}
+}
@@ -9,10 +9,10 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/ms3"
 )
 
 // AvatarAmazonS3 ...
@@ -21,13 +21,14 @@ type AvatarAmazonS3 struct {
 	uploader *s3manager.Uploader
 
 	bucket string
+	// publicURL is the base url avatars are served from - the bucket is used as host if empty
+	publicURL string
 }
 
 // NewAvatarAmazonS3 init an S3 session
-func NewAvatarAmazonS3(region, bucket string) (*AvatarAmazonS3, error) {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(region)},
-	)
+// any S3-compatible storage can be used through the configuration
+func NewAvatarAmazonS3(cfg ms3.Config, bucket, publicURL string) (*AvatarAmazonS3, error) {
+	sess, err := ms3.NewSession(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("could not create aws session (%v)", err)
 	}
@@ -38,6 +39,8 @@ func NewAvatarAmazonS3(region, bucket string) (*AvatarAmazonS3, error) {
 		session:  s3Cli,
 		uploader: s3manager.NewUploaderWithClient(s3Cli),
 		bucket:   bucket,
+
+		publicURL: publicURL,
 	}
 	return s, nil
 }
@@ -58,6 +61,10 @@ func (s *AvatarAmazonS3) Upload(ctx context.Context, avatar *AvatarFile) (string
 		return "", merr.Internal().Descf("unable to upload %q to %q, %v", s.getKey(avatar), s.bucket, err)
 	}
 
+	if s.publicURL != "" {
+		return strings.TrimSuffix(s.publicURL, "/") + "/" + s.getKey(avatar), nil
+	}
+
 	avatarURL, _ := url.Parse(uo.Location)
 	avatarURL.Host = s.bucket
 	avatarURL.Path = strings.TrimPrefix(avatarURL.Path, "/"+s.bucket)
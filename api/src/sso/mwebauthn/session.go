@@ -37,3 +37,9 @@ func GetSession(redConn *redis.Client, identityID, challenge string) (webauthn.S
 
 	return sessionData, nil
 }
+
+// DeleteSession from redis
+func DeleteSession(redConn *redis.Client, identityID, challenge string) error {
+	_, err := redConn.Del(fmt.Sprintf("%s:%s:webauthn", identityID, challenge)).Result()
+	return err
+}
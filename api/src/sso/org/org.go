@@ -2,6 +2,7 @@ package org
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"gitlab.misakey.dev/misakey/backend/api/src/box/events"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/merr"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/repositories/sqlboiler"
 )
 
@@ -25,19 +27,34 @@ type Org struct {
 	// for now, this is ignored
 	Domain  null.String `json:"-"` // https://gitlab.misakey.dev/misakey/user-needs/-/issues/392
 	LogoURL null.String `json:"-"` // https://gitlab.misakey.dev/misakey/user-needs/-/issues/395
+
+	// MaxFileSize overrides the configured maximum size of files sent to boxes owned by the organization
+	// and of files uploaded by its members
+	MaxFileSize null.Int64 `json:"max_file_size"`
+	// AuthnPolicy restricts the authentication policy of the members of the organization
+	AuthnPolicy *oidc.PolicyRestriction `json:"authn_policy"`
 }
 
 func newOrg() *Org { return &Org{} }
 
-func (o Org) toSQLBoiler() *sqlboiler.Organization {
-	return &sqlboiler.Organization{
-		ID:        o.ID,
-		Name:      o.Name,
-		CreatorID: o.CreatorID,
-		Domain:    o.Domain,
-		LogoURL:   o.LogoURL,
-		CreatedAt: o.CreatedAt,
+func (o Org) toSQLBoiler() (*sqlboiler.Organization, error) {
+	record := &sqlboiler.Organization{
+		ID:          o.ID,
+		Name:        o.Name,
+		CreatorID:   o.CreatorID,
+		Domain:      o.Domain,
+		LogoURL:     o.LogoURL,
+		CreatedAt:   o.CreatedAt,
+		MaxFileSize: o.MaxFileSize,
 	}
+	if o.AuthnPolicy != nil {
+		policy, err := json.Marshal(o.AuthnPolicy)
+		if err != nil {
+			return nil, merr.From(err).Desc("marshaling authn policy")
+		}
+		record.AuthnPolicy = null.JSONFrom(policy)
+	}
+	return record, nil
 }
 
 func (o *Org) fromSQLBoiler(src sqlboiler.Organization) *Org {
@@ -47,6 +64,15 @@ func (o *Org) fromSQLBoiler(src sqlboiler.Organization) *Org {
 	o.Domain = src.Domain
 	o.LogoURL = src.LogoURL
 	o.CreatedAt = src.CreatedAt
+	o.MaxFileSize = src.MaxFileSize
+	o.AuthnPolicy = nil
+	if src.AuthnPolicy.Valid {
+		var policy oidc.PolicyRestriction
+		// the policy is validated before being stored
+		if err := json.Unmarshal(src.AuthnPolicy.JSON, &policy); err == nil {
+			o.AuthnPolicy = &policy
+		}
+	}
 	return o
 }
 
@@ -66,7 +92,11 @@ func Create(ctx context.Context, exec boil.ContextExecutor, creatorID, name stri
 		CreatorID: creatorID,
 	}
 
-	if err := o.toSQLBoiler().Insert(ctx, exec, boil.Infer()); err != nil {
+	record, err := o.toSQLBoiler()
+	if err != nil {
+		return o, err
+	}
+	if err := record.Insert(ctx, exec, boil.Infer()); err != nil {
 		return o, err
 	}
 	return o, err
@@ -81,6 +111,80 @@ func GetOrg(ctx context.Context, exec boil.ContextExecutor, id string) (*Org, er
 	return newOrg().fromSQLBoiler(*record), nil
 }
 
+// UpdateAuthnPolicy of the organization - a nil policy removes it
+func UpdateAuthnPolicy(ctx context.Context, exec boil.ContextExecutor, id string, policy *oidc.PolicyRestriction) error {
+	record, err := Org{AuthnPolicy: policy}.toSQLBoiler()
+	if err != nil {
+		return err
+	}
+	data := sqlboiler.M{sqlboiler.OrganizationColumns.AuthnPolicy: record.AuthnPolicy}
+	rowsAff, err := sqlboiler.Organizations(sqlboiler.OrganizationWhere.ID.EQ(id)).UpdateAll(ctx, exec, data)
+	if err != nil {
+		return merr.From(err).Desc("updating authn policy")
+	}
+	if rowsAff == 0 {
+		return merr.NotFound().Desc("no rows affected in persistent layer")
+	}
+	return nil
+}
+
+// UpdateMaxFileSize of the organization - a null size removes the override
+func UpdateMaxFileSize(ctx context.Context, exec boil.ContextExecutor, id string, maxFileSize null.Int64) error {
+	data := sqlboiler.M{sqlboiler.OrganizationColumns.MaxFileSize: maxFileSize}
+	rowsAff, err := sqlboiler.Organizations(sqlboiler.OrganizationWhere.ID.EQ(id)).UpdateAll(ctx, exec, data)
+	if err != nil {
+		return merr.From(err).Desc("updating max file size")
+	}
+	if rowsAff == 0 {
+		return merr.NotFound().Desc("no rows affected in persistent layer")
+	}
+	return nil
+}
+
+// ListForMember returns the organizations the identity is a member of:
+// only the identities having a role in the organization are members - its creator so far,
+// being a member of a box owned by the organization does not make a member of the organization.
+func ListForMember(ctx context.Context, exec boil.ContextExecutor, identityID string) ([]Org, error) {
+	return ListByIDsOrCreatorID(ctx, exec, nil, identityID)
+}
+
+// GetMemberMaxFileSize returns the maximum size of files uploaded by the identity outside of boxes:
+// the largest override of its organizations, the configured maximum if none overrides it
+func GetMemberMaxFileSize(ctx context.Context, exec boil.ContextExecutor, identityID string, configured int64) (int64, error) {
+	orgs, err := ListForMember(ctx, exec, identityID)
+	if err != nil {
+		return 0, merr.From(err).Desc("listing member orgs")
+	}
+	maxSize := configured
+	overridden := false
+	for _, o := range orgs {
+		if !o.MaxFileSize.Valid {
+			continue
+		}
+		if !overridden || o.MaxFileSize.Int64 > maxSize {
+			maxSize = o.MaxFileSize.Int64
+			overridden = true
+		}
+	}
+	return maxSize, nil
+}
+
+// GetAuthnPolicy of an identity: the instance policy restricted by the policies of its organizations.
+// Only the members of the organizations are concerned, not the members of their boxes.
+func GetAuthnPolicy(ctx context.Context, exec boil.ContextExecutor, identityID string) (oidc.Policy, error) {
+	policy := oidc.GetPolicy()
+	orgs, err := ListForMember(ctx, exec, identityID)
+	if err != nil {
+		return policy, merr.From(err).Desc("listing member orgs")
+	}
+	for _, o := range orgs {
+		if o.AuthnPolicy != nil {
+			policy = policy.Restrict(*o.AuthnPolicy)
+		}
+	}
+	return policy, nil
+}
+
 func MustBeAdmin(ctx context.Context, exec boil.ContextExecutor, orgID string, identityID string) error {
 	org, err := GetOrg(ctx, exec, orgID)
 	if err != nil {
@@ -24,49 +24,82 @@ import (
 
 // Organization is an object representing the database table.
 type Organization struct {
-	ID        string      `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Name      string      `boil:"name" json:"name" toml:"name" yaml:"name"`
-	Domain    null.String `boil:"domain" json:"domain,omitempty" toml:"domain" yaml:"domain,omitempty"`
-	LogoURL   null.String `boil:"logo_url" json:"logo_url,omitempty" toml:"logo_url" yaml:"logo_url,omitempty"`
-	CreatorID string      `boil:"creator_id" json:"creator_id" toml:"creator_id" yaml:"creator_id"`
-	CreatedAt time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	ID          string      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name        string      `boil:"name" json:"name" toml:"name" yaml:"name"`
+	Domain      null.String `boil:"domain" json:"domain,omitempty" toml:"domain" yaml:"domain,omitempty"`
+	LogoURL     null.String `boil:"logo_url" json:"logo_url,omitempty" toml:"logo_url" yaml:"logo_url,omitempty"`
+	CreatorID   string      `boil:"creator_id" json:"creator_id" toml:"creator_id" yaml:"creator_id"`
+	CreatedAt   time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	MaxFileSize null.Int64  `boil:"max_file_size" json:"max_file_size,omitempty" toml:"max_file_size" yaml:"max_file_size,omitempty"`
+	AuthnPolicy null.JSON   `boil:"authn_policy" json:"authn_policy,omitempty" toml:"authn_policy" yaml:"authn_policy,omitempty"`
 
 	R *organizationR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L organizationL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var OrganizationColumns = struct {
-	ID        string
-	Name      string
-	Domain    string
-	LogoURL   string
-	CreatorID string
-	CreatedAt string
+	ID          string
+	Name        string
+	Domain      string
+	LogoURL     string
+	CreatorID   string
+	CreatedAt   string
+	MaxFileSize string
+	AuthnPolicy string
 }{
-	ID:        "id",
-	Name:      "name",
-	Domain:    "domain",
-	LogoURL:   "logo_url",
-	CreatorID: "creator_id",
-	CreatedAt: "created_at",
+	ID:          "id",
+	Name:        "name",
+	Domain:      "domain",
+	LogoURL:     "logo_url",
+	CreatorID:   "creator_id",
+	CreatedAt:   "created_at",
+	MaxFileSize: "max_file_size",
+	AuthnPolicy: "authn_policy",
 }
 
 // Generated where
 
+type whereHelpernull_Int64 struct{ field string }
+
+func (w whereHelpernull_Int64) EQ(x null.Int64) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Int64) NEQ(x null.Int64) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Int64) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Int64) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+func (w whereHelpernull_Int64) LT(x null.Int64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Int64) LTE(x null.Int64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Int64) GT(x null.Int64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Int64) GTE(x null.Int64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
 var OrganizationWhere = struct {
-	ID        whereHelperstring
-	Name      whereHelperstring
-	Domain    whereHelpernull_String
-	LogoURL   whereHelpernull_String
-	CreatorID whereHelperstring
-	CreatedAt whereHelpertime_Time
+	ID          whereHelperstring
+	Name        whereHelperstring
+	Domain      whereHelpernull_String
+	LogoURL     whereHelpernull_String
+	CreatorID   whereHelperstring
+	CreatedAt   whereHelpertime_Time
+	MaxFileSize whereHelpernull_Int64
+	AuthnPolicy whereHelpernull_JSON
 }{
-	ID:        whereHelperstring{field: "\"organization\".\"id\""},
-	Name:      whereHelperstring{field: "\"organization\".\"name\""},
-	Domain:    whereHelpernull_String{field: "\"organization\".\"domain\""},
-	LogoURL:   whereHelpernull_String{field: "\"organization\".\"logo_url\""},
-	CreatorID: whereHelperstring{field: "\"organization\".\"creator_id\""},
-	CreatedAt: whereHelpertime_Time{field: "\"organization\".\"created_at\""},
+	ID:          whereHelperstring{field: "\"organization\".\"id\""},
+	Name:        whereHelperstring{field: "\"organization\".\"name\""},
+	Domain:      whereHelpernull_String{field: "\"organization\".\"domain\""},
+	LogoURL:     whereHelpernull_String{field: "\"organization\".\"logo_url\""},
+	CreatorID:   whereHelperstring{field: "\"organization\".\"creator_id\""},
+	CreatedAt:   whereHelpertime_Time{field: "\"organization\".\"created_at\""},
+	MaxFileSize: whereHelpernull_Int64{field: "\"organization\".\"max_file_size\""},
+	AuthnPolicy: whereHelpernull_JSON{field: "\"organization\".\"authn_policy\""},
 }
 
 // OrganizationRels is where relationship names are stored.
@@ -93,8 +126,8 @@ func (*organizationR) NewStruct() *organizationR {
 type organizationL struct{}
 
 var (
-	organizationAllColumns            = []string{"id", "name", "domain", "logo_url", "creator_id", "created_at"}
-	organizationColumnsWithoutDefault = []string{"id", "name", "domain", "logo_url", "creator_id"}
+	organizationAllColumns            = []string{"id", "name", "domain", "logo_url", "creator_id", "created_at", "max_file_size", "authn_policy"}
+	organizationColumnsWithoutDefault = []string{"id", "name", "domain", "logo_url", "creator_id", "max_file_size", "authn_policy"}
 	organizationColumnsWithDefault    = []string{"created_at"}
 	organizationPrimaryKeyColumns     = []string{"id"}
 )
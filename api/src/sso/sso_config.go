@@ -4,8 +4,12 @@ import (
 	"os"
 
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oidc"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/federation"
 )
 
 func initConfig() {
@@ -34,7 +38,7 @@ func initConfig() {
 	}
 	switch os.Getenv("ENV") {
 	case "production":
-		mandatoryFields = append(mandatoryFields, []string{"aws.ses_region", "aws.s3_region", "aws.user_content_bucket"}...)
+		mandatoryFields = append(mandatoryFields, []string{"aws.ses_region"}...)
 		if os.Getenv("AWS_ACCESS_KEY") == "" {
 			log.Warn().Msg("AWS_ACCESS_KEY not set")
 		}
@@ -42,14 +46,80 @@ func initConfig() {
 			log.Warn().Msg("AWS_SECRET_KEY not set")
 		}
 	case "development":
-		mandatoryFields = append(mandatoryFields, []string{"server.avatars", "server.avatar_url"}...)
 		log.Info().Msg("{} Development mode is activated. {}")
 	default:
 		log.Fatal().Msg("unknown ENV value (should be production|development)")
 	}
+	mandatoryFields = append(mandatoryFields, config.StorageMandatoryFields(
+		[]string{"server.avatars", "server.avatar_url"},
+		[]string{"aws.user_content_bucket"},
+		[]string{"s3.user_content_bucket"},
+	)...)
 	config.FatalIfMissing("SSO", mandatoryFields)
 	secretFields := []string{
 		"authflow.self_encoded_jwk",
 	}
+	for name := range viper.GetStringMap("federation.providers") {
+		secretFields = append(secretFields, "federation.providers."+name+".client_secret")
+	}
 	config.Print("SSO", secretFields)
 }
+
+// authnPolicy reads the authn_policy section of the configuration,
+// the configured levels replace the default ones
+func authnPolicy() oidc.Policy {
+	policy := oidc.DefaultPolicy()
+	if !viper.IsSet("authn_policy") {
+		return policy
+	}
+	if err := viper.UnmarshalKey("authn_policy", &policy); err != nil {
+		log.Fatal().Err(err).Msg("could not read authn policy")
+	}
+	return policy
+}
+
+// federationProviders reads the federation.providers section of the configuration,
+// providers are indexed by their name
+func federationProviders() map[string]federation.Provider {
+	providers := map[string]federation.Provider{}
+	if !viper.IsSet("federation.providers") {
+		return providers
+	}
+	if err := viper.UnmarshalKey("federation.providers", &providers); err != nil {
+		log.Fatal().Err(err).Msg("could not read federation providers")
+	}
+	for name, provider := range providers {
+		if err := provider.Validate(); err != nil {
+			log.Fatal().Err(err).Msgf("invalid federation provider %q", name)
+		}
+	}
+	if len(providers) > 0 && viper.GetString("federation.redirect_url") == "" {
+		log.Fatal().Msg("federation.redirect_url is required to use federation providers")
+	}
+	return providers
+}
+
+// authnAttemptLimits reads the authn_attempts section of the configuration,
+// missing values are taken from the default limits
+func authnAttemptLimits() authn.AttemptLimits {
+	limits := authn.DefaultAttemptLimits
+	if viper.IsSet("authn_attempts.max_identity_failures") {
+		limits.MaxIdentityFailures = viper.GetInt("authn_attempts.max_identity_failures")
+	}
+	if viper.IsSet("authn_attempts.max_ip_failures") {
+		limits.MaxIPFailures = viper.GetInt("authn_attempts.max_ip_failures")
+	}
+	if viper.IsSet("authn_attempts.max_code_failures") {
+		limits.MaxCodeFailures = viper.GetInt("authn_attempts.max_code_failures")
+	}
+	if viper.IsSet("authn_attempts.base_lockout") {
+		limits.BaseLockout = viper.GetDuration("authn_attempts.base_lockout")
+	}
+	if viper.IsSet("authn_attempts.max_lockout") {
+		limits.MaxLockout = viper.GetDuration("authn_attempts.max_lockout")
+	}
+	if viper.IsSet("authn_attempts.window") {
+		limits.Window = viper.GetDuration("authn_attempts.window")
+	}
+	return limits
+}
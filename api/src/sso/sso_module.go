@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/viper"
 
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/authz"
+	"gitlab.misakey.dev/misakey/backend/api/src/sdk/config"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/db"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/mredis"
 	"gitlab.misakey.dev/misakey/backend/api/src/sdk/oauth"
@@ -23,6 +24,7 @@ import (
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/application/authflow"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/authn"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/crypto"
+	"gitlab.misakey.dev/misakey/backend/api/src/sso/federation"
 	"gitlab.misakey.dev/misakey/backend/api/src/sso/identity"
 )
 
@@ -30,6 +32,11 @@ import (
 func InitModule(router *echo.Echo) Process {
 	initConfig()
 
+	// set the authentication policy used to compute ACRs
+	if err := oidc.SetPolicy(authnPolicy()); err != nil {
+		log.Fatal().Err(err).Msg("invalid authn policy")
+	}
+
 	// init db connections
 	ssoDBConn, err := db.NewPSQLConn(
 		os.Getenv("DSN_SSO"),
@@ -97,7 +104,7 @@ func InitModule(router *echo.Echo) Process {
 	protectedPublicHydraFORM := http.NewClient(publicURL, secure, http.SetFormat(http.MimeTypeURLEncodedForm), http.SetAuthenticator(oidc.NewPrivateKeyJWTAuthenticator(selfAuth)))
 
 	// init repositories
-	authnSessionRepo := authn.NewAuthnSessionRedis(simpleKeyRedis)
+	authnSessionRepo := authn.NewAuthnSessionRedis(simpleKeyRedis, redConn)
 	authnProcessRepo := authn.NewAuthnProcessRedis(simpleKeyRedis)
 	hydraRepo := authflow.NewHydraHTTP(publicHydraJSON, adminHydraJSON, adminHydraFORM, protectedPublicHydraFORM)
 	templateRepo := email.NewTemplateFileSystem(viper.GetString("mail.templates"))
@@ -106,16 +113,24 @@ func InitModule(router *echo.Echo) Process {
 	env := os.Getenv("ENV")
 	if env == "development" {
 		emailRepo = email.NewLogMailer()
-		avatarRepo = identity.NewAvatarFileSystem(viper.GetString("server.avatars"), viper.GetString("server.avatar_url"))
 	} else if env == "production" {
 		emailRepo = email.NewMailerAmazonSES(viper.GetString("aws.ses_region"), viper.GetString("aws.ses_configuration_set"))
-		avatarRepo, err = identity.NewAvatarAmazonS3(viper.GetString("aws.s3_region"), viper.GetString("aws.user_content_bucket"))
-		if err != nil {
-			log.Fatal().Msg("could not initiate AWS S3 avatar bucket connection")
-		}
 	} else {
 		log.Fatal().Msg("unknown ENV value (should be production|development)")
 	}
+	switch config.StorageBackend() {
+	case config.FileSystemStorage:
+		avatarRepo = identity.NewAvatarFileSystem(viper.GetString("server.avatars"), viper.GetString("server.avatar_url"))
+	case config.AmazonS3Storage:
+		avatarRepo, err = identity.NewAvatarAmazonS3(config.S3Config(), viper.GetString("aws.user_content_bucket"), "")
+	case config.S3CompatibleStorage:
+		avatarRepo, err = identity.NewAvatarAmazonS3(config.S3Config(), viper.GetString("s3.user_content_bucket"), viper.GetString("s3.user_content_url"))
+	default:
+		log.Fatal().Msgf("unknown storage backend %q", config.StorageBackend())
+	}
+	if err != nil {
+		log.Fatal().Msg("could not initiate S3 avatar bucket connection")
+	}
 	emailRenderer, err := email.NewEmailRenderer(
 		templateRepo,
 		[]string{
@@ -140,15 +155,23 @@ func InitModule(router *echo.Echo) Process {
 		authnSessionRepo, authnProcessRepo,
 		emailRenderer, emailRepo,
 		webauthnHandler, viper.GetString("authflow.app_name"),
+		authnAttemptLimits(),
+	)
+	federationService := federation.NewService(
+		federationProviders(),
+		viper.GetString("federation.redirect_url"),
+		simpleKeyRedis,
 	)
 	backupKeyShareService := crypto.NewBackupKeyShareService(simpleKeyRedis, viper.GetDuration("backup_key_share.expiration"))
 	ssoService := application.NewSSOService(
 		identityService,
 		authFlowService,
 		authenticationService,
+		federationService,
 		backupKeyShareService,
 		viper.GetDuration("root_key_share.expiration"),
 		selfCliID,
+		config.GetUploadLimits(),
 
 		ssoDBConn,
 		boxDBConn,
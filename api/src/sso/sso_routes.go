@@ -171,6 +171,24 @@ func bindRoutes(
 		ss.AckIdentityNotification,
 		request.ResponseNoContent,
 	))
+	identityPath.GET(selfOIDCHandlers.NewACR1(
+		"/:id/sessions",
+		func() request.Request { return &application.IdentitySessionsQuery{} },
+		ss.ListIdentitySessions,
+		request.ResponseOK,
+	))
+	identityPath.DELETE(selfOIDCHandlers.NewACR1(
+		"/:id/sessions",
+		func() request.Request { return &application.IdentitySessionsQuery{} },
+		ss.RevokeIdentitySessions,
+		request.ResponseNoContent,
+	))
+	identityPath.DELETE(selfOIDCHandlers.NewACR1(
+		"/:id/sessions/:session-id",
+		func() request.Request { return &application.IdentitySessionCmd{} },
+		ss.RevokeIdentitySession,
+		request.ResponseNoContent,
+	))
 	identityPath.GET(selfOIDCHandlers.NewACR1(
 		"/:id/organizations",
 		func() request.Request { return &application.OrgListQuery{} },
@@ -259,6 +277,20 @@ func bindRoutes(
 		request.ResponseOK,
 	))
 
+	orgPath.PUT(selfOIDCHandlers.NewACR2(
+		"/:id/authn-policy",
+		func() request.Request { return &application.SetOrgAuthnPolicyCmd{} },
+		ss.SetOrgAuthnPolicy,
+		request.ResponseNoContent,
+	))
+
+	orgPath.PUT(selfOIDCHandlers.NewACR2(
+		"/:id/max-file-size",
+		func() request.Request { return &application.SetOrgMaxFileSizeCmd{} },
+		ss.SetOrgMaxFileSize,
+		request.ResponseNoContent,
+	))
+
 	orgPath.GET(selfOIDCHandlers.NewPublic(
 		"/:id/public",
 		func() request.Request { return &application.GetOrgPublicRequest{} },
@@ -307,7 +339,7 @@ func bindRoutes(
 		"/authn-steps",
 		func() request.Request { return &application.AuthenticationStepCmd{} },
 		ss.InitAuthnStep,
-		request.ResponseNoContent,
+		request.ResponseOKOrNoContent,
 	))
 
 	// AUTH
@@ -349,6 +381,51 @@ func bindRoutes(
 		ss.RequireIdentity,
 		request.ResponseOK,
 	))
+	// login with upstream providers
+	authPath.GET(authnProcessHandlers.NewPublic(
+		"/federation/:provider/login",
+		func() request.Request { return &application.FederatedLoginQuery{} },
+		ss.FederatedLogin,
+		func(c echo.Context, data interface{}) error {
+			return request.ResponseRedirectFound(c, data.(application.FederatedLoginView).RedirectTo)
+		},
+		ss.SetFederationCookie,
+	))
+	authPath.GET(authnProcessHandlers.NewPublic(
+		"/federation/callback",
+		func() request.Request { return &application.FederatedCallbackQuery{} },
+		ss.FederatedCallback,
+		func(c echo.Context, data interface{}) error {
+			return request.ResponseRedirectFound(c, data.(application.FederatedCallbackView).RedirectTo)
+		},
+		ss.CleanFederationCookie,
+		ss.SetFederatedAuthnCookie,
+	))
+	// MFA enrollment required by the organizations of the identity to end the login flow
+	authPath.GET(authnProcessHandlers.NewACR2(
+		"/identities/:id/webauthn-credentials/create",
+		func() request.Request { return &application.BeginWebAuthnRegistrationQuery{} },
+		ss.BeginWebAuthnRegistration,
+		request.ResponseOK,
+	))
+	authPath.POST(authnProcessHandlers.NewACR2(
+		"/identities/:id/webauthn-credentials/create",
+		func() request.Request { return &application.FinishWebAuthnRegistrationQuery{} },
+		ss.FinishWebAuthnRegistration,
+		request.ResponseOK,
+	))
+	authPath.GET(authnProcessHandlers.NewACR2(
+		"/identities/:id/totp/enroll",
+		func() request.Request { return &application.BeginTOTPEnrollmentQuery{} },
+		ss.BeginTOTPEnrollment,
+		request.ResponseOK,
+	))
+	authPath.POST(authnProcessHandlers.NewACR2(
+		"/identities/:id/totp/enroll",
+		func() request.Request { return &application.FinishTOTPEnrollmentQuery{} },
+		ss.FinishTOTPEnrollment,
+		request.ResponseOK,
+	))
 
 	// consent flow
 	authPath.GET(authnProcessHandlers.NewPublic(